@@ -1,19 +1,32 @@
 package rd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 )
 
 const (
 	authBaseUrl = "https://api.real-debrid.com/oauth/v2"
 
-	deviceUrl      = authBaseUrl + "/device/code"
-	credentialsUrl = authBaseUrl + "/device/credentials"
-	tokenUrl       = authBaseUrl + "/token"
+	devicePath      = "/device/code"
+	credentialsPath = "/device/credentials"
+	tokenPath       = "/token"
 
 	defaultClientID = "X245A4XAIBGVM"
+
+	defaultPollInterval = 5 * time.Second
+)
+
+var (
+	// ErrAuthorizationPending is returned while the user has not yet approved the device
+	ErrAuthorizationPending = errors.New("secrets not authorized")
+	// ErrDeviceCodeExpired is returned when the user did not approve the device in time
+	ErrDeviceCodeExpired = errors.New("device code expired")
 )
 
 type (
@@ -21,6 +34,14 @@ type (
 		HTTPDoer
 	}
 
+	// authDoer holds the configuration of an AuthClient, so its struct keeps a single field
+	authDoer struct {
+		HTTPDoer
+
+		baseURL string
+		clock   *Clock
+	}
+
 	Verification struct {
 		DeviceCode            string `json:"device_code"`
 		UserCode              string `json:"user_code"`
@@ -35,14 +56,65 @@ type (
 	}
 )
 
-func NewAuthClient(doer HTTPDoer) *AuthClient {
-	return &AuthClient{doer}
+func NewAuthClient(doer HTTPDoer, options ...func(*AuthClient)) *AuthClient {
+	c := &AuthClient{HTTPDoer: doer}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// AuthBaseURL makes the client send its requests to the given OAuth base URL instead of the RealDebrid one
+func AuthBaseURL(url string) func(*AuthClient) {
+	return func(c *AuthClient) {
+		c.config().baseURL = strings.TrimRight(url, "/")
+	}
+}
+
+// AuthClock makes the client record the offset of the given clock in the tokens it obtains
+func AuthClock(clock *Clock) func(*AuthClient) {
+	return func(c *AuthClient) {
+		c.config().clock = clock
+	}
+}
+
+// config returns the configuration of the client, wrapping its doer to hold it if needed
+func (c *AuthClient) config() *authDoer {
+	if d, ok := c.HTTPDoer.(*authDoer); ok {
+		return d
+	}
+
+	d := &authDoer{HTTPDoer: c.HTTPDoer}
+	c.HTTPDoer = d
+	return d
+}
+
+// clock returns the clock the client is configured with, nil for the local time
+func (c *AuthClient) clock() *Clock {
+	if d, ok := c.HTTPDoer.(*authDoer); ok {
+		return d.clock
+	}
+	return nil
+}
+
+func (c *AuthClient) url(path string) string {
+	if d, ok := c.HTTPDoer.(*authDoer); ok && d.baseURL != "" {
+		return d.baseURL + path
+	}
+	return authBaseUrl + path
 }
 
 // StartAuthentication starts the authentication flow for the service
 // RealDebrid API information: https://api.real-debrid.com/#device_auth_no_secret
 func (c *AuthClient) StartAuthentication(clientID string) (v Verification, err error) {
-	resp, err := httpGet(c, deviceUrl, map[string]string{"client_id": clientID, "new_credentials": "yes"})
+	return c.StartAuthenticationContext(context.Background(), clientID)
+}
+
+// StartAuthenticationContext is like StartAuthentication but uses the given context for the requests
+func (c *AuthClient) StartAuthenticationContext(ctx context.Context, clientID string) (v Verification, err error) {
+	resp, err := httpGet(ctx, c, c.url(devicePath), map[string]string{"client_id": clientID, "new_credentials": "yes"})
 	if err != nil {
 		return v, err
 	}
@@ -55,7 +127,12 @@ func (c *AuthClient) StartAuthentication(clientID string) (v Verification, err e
 // ObtainSecret returns the HTTPClient ID and HTTPClient secret that are used for
 // obtaining a valid token in the next step
 func (c *AuthClient) ObtainSecret(deviceCode, clientID string) (secrets Secrets, err error) {
-	resp, err := httpGet(c, credentialsUrl, map[string]string{"client_id": clientID, "code": deviceCode})
+	return c.ObtainSecretContext(context.Background(), deviceCode, clientID)
+}
+
+// ObtainSecretContext is like ObtainSecret but uses the given context for the requests
+func (c *AuthClient) ObtainSecretContext(ctx context.Context, deviceCode, clientID string) (secrets Secrets, err error) {
+	resp, err := httpGet(ctx, c, c.url(credentialsPath), map[string]string{"client_id": clientID, "code": deviceCode})
 	if err != nil {
 		return secrets, err
 	}
@@ -63,14 +140,73 @@ func (c *AuthClient) ObtainSecret(deviceCode, clientID string) (secrets Secrets,
 	defer resp.Body.Close()
 	err = json.NewDecoder(resp.Body).Decode(&secrets)
 	if secrets.ClientID == "" || secrets.ClientSecret == "" {
-		return secrets, fmt.Errorf("secrets not authorized")
+		return secrets, ErrAuthorizationPending
 	}
 	return secrets, err
 }
 
+// AuthenticateDevice runs the whole device authentication flow. The verification is passed
+// to onPrompt so the user can be asked to approve the device, after which the credentials
+// are polled until they are approved, the device code expires or the context is done. The
+// expiry is relative to the start of the flow, so it only uses the local clock, as an offset to
+// the server time would cancel out.
+// RealDebrid API information: https://api.real-debrid.com/#device_auth_no_secret
+func (c *AuthClient) AuthenticateDevice(ctx context.Context, clientID string, onPrompt func(Verification)) (t Token, secrets Secrets, err error) {
+	v, err := c.StartAuthenticationContext(ctx, clientID)
+	if err != nil {
+		return t, secrets, err
+	}
+
+	if onPrompt != nil {
+		onPrompt(v)
+	}
+
+	interval := time.Duration(v.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	deadline := time.Now().Add(time.Duration(v.ExpiresIn) * time.Second)
+
+	for {
+		secrets, err = c.ObtainSecretContext(ctx, v.DeviceCode, clientID)
+		if err == nil {
+			break
+		}
+		if !isAuthorizationPending(err) {
+			return t, secrets, err
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return t, secrets, ErrDeviceCodeExpired
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return t, secrets, err
+		}
+	}
+
+	t, err = c.ObtainAccessTokenContext(ctx, secrets.ClientID, secrets.ClientSecret, v.DeviceCode)
+	return t, secrets, err
+}
+
+// isAuthorizationPending checks if the credentials are not approved yet. Until then, the API
+// answers either with empty credentials or with a forbidden status.
+func isAuthorizationPending(err error) bool {
+	if err == ErrAuthorizationPending {
+		return true
+	}
+
+	apiErr, ok := err.(APIError)
+	return ok && apiErr.StatusCode == http.StatusForbidden
+}
+
 // ObtainAccessToken tries to get a new token from the service
 func (c *AuthClient) ObtainAccessToken(clientID, secret, code string) (t Token, err error) {
-	resp, err := httpPostForm(c, tokenUrl, map[string]string{
+	return c.ObtainAccessTokenContext(context.Background(), clientID, secret, code)
+}
+
+// ObtainAccessTokenContext is like ObtainAccessToken but uses the given context for the requests
+func (c *AuthClient) ObtainAccessTokenContext(ctx context.Context, clientID, secret, code string) (t Token, err error) {
+	resp, err := httpPostForm(ctx, c, c.url(tokenPath), map[string]string{
 		"client_id":     clientID,
 		"client_secret": secret,
 		"code":          code,
@@ -81,21 +217,36 @@ func (c *AuthClient) ObtainAccessToken(clientID, secret, code string) (t Token,
 	}
 
 	t.ObtainedAt = time.Now()
+	if offset, synced := c.clock().synced(); synced {
+		t.ClockOffset = &offset
+	}
+	t.ClientID = clientID
+	t.ClientSecret = secret
 	defer resp.Body.Close()
 	err = json.NewDecoder(resp.Body).Decode(&t)
 	return t, err
 }
 
-// RefreshAccessToken tries to refresh the given token and get a new one
+// RefreshAccessToken tries to refresh the given token and get a new one. The client credentials
+// stored in the token are used if present, otherwise they are obtained for the default client ID.
 func (c *AuthClient) RefreshAccessToken(token Token) (t Token, err error) {
+	return c.RefreshAccessTokenContext(context.Background(), token)
+}
+
+// RefreshAccessTokenContext is like RefreshAccessToken but uses the given context for the requests
+func (c *AuthClient) RefreshAccessTokenContext(ctx context.Context, token Token) (t Token, err error) {
 	if token.RefreshToken == "" {
 		return t, fmt.Errorf("cannot reauthorize without refresh token")
 	}
 
-	secrets, err := c.ObtainSecret(token.RefreshToken, defaultClientID)
+	if token.ClientID != "" && token.ClientSecret != "" {
+		return c.ObtainAccessTokenContext(ctx, token.ClientID, token.ClientSecret, token.RefreshToken)
+	}
+
+	secrets, err := c.ObtainSecretContext(ctx, token.RefreshToken, defaultClientID)
 	if err != nil {
 		return t, err
 	}
 
-	return c.ObtainAccessToken(secrets.ClientID, secrets.ClientSecret, token.RefreshToken)
+	return c.ObtainAccessTokenContext(ctx, secrets.ClientID, secrets.ClientSecret, token.RefreshToken)
 }
@@ -2,6 +2,7 @@ package rd_test
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -149,7 +150,7 @@ func TestAuthClient_ErrorsOnFailedObtainingToken(t *testing.T) {
 
 	token, err := client.ObtainAccessToken("0N2RHHK5OKNIX", "135d1b6dc60dddbcaa2e5dc1772c85d56c5479ba", "ZD7HNOMEXOJY7P2FP4XIJA5E634RWZKWWQ6RZNJJT235G4RNCAOP")
 	assert.Empty(t, token)
-	assert.EqualError(t, err, "error_message: wrong_parameter\nerror_code: 2 - Unknown error\nerror_details: \nstatus_code: 400\n")
+	assert.EqualError(t, err, "error_message: wrong_parameter\nerror_code: 2 - Bad parameter value\nerror_details: \nstatus_code: 400\n")
 }
 
 func TestAuthClient_CanObtainAccessTokenFromRefreshToken(t *testing.T) {
@@ -197,4 +198,140 @@ func TestAuthClient_CanObtainAccessTokenFromRefreshToken(t *testing.T) {
 	assert.Equal(t, expectedToken.AccessToken, token.AccessToken)
 	assert.Equal(t, expectedToken.TokenType, token.TokenType)
 	assert.Equal(t, expectedToken.RefreshToken, token.RefreshToken)
+	assert.Equal(t, "0N2RHHK5OKNIX", token.ClientID)
+	assert.Equal(t, "135d1b6dc60dddbcaa2e5dc1772c85d56c5479ba", token.ClientSecret)
+}
+
+func TestAuthClient_AuthenticateDevicePollsUntilApproved(t *testing.T) {
+	polls := 0
+	client := NewAuthTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/oauth/v2/device/code":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"device_code": "DEVICE_CODE", "user_code": "EBKEG2RR", "interval": 1, "expires_in": 600, "verification_url": "https://real-debrid.com/device"}`)),
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		case "/oauth/v2/device/credentials":
+			assert.Equal(t, "DEVICE_CODE", req.URL.Query().Get("code"))
+			polls++
+			if polls == 1 {
+				return &http.Response{
+					StatusCode: http.StatusForbidden,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "permission_denied", "error_code": 9 }`)),
+					Header:     map[string][]string{"Content-Type": {"application/json"}},
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"client_id":"0N2RHHK5OKNIX","client_secret":"135d1b6dc60dddbcaa2e5dc1772c85d56c5479ba"}`)),
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		case "/oauth/v2/token":
+			assert.Equal(t, "0N2RHHK5OKNIX", req.FormValue("client_id"))
+			assert.Equal(t, "135d1b6dc60dddbcaa2e5dc1772c85d56c5479ba", req.FormValue("client_secret"))
+			assert.Equal(t, "DEVICE_CODE", req.FormValue("code"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"access_token": "ACCESS_TOKEN", "expires_in": 3600, "refresh_token": "REFRESH_TOKEN", "token_type": "Bearer" }`)),
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		}
+		t.Errorf("unexpected request to %s", req.URL)
+		return nil
+	})
+
+	var prompted rd.Verification
+	token, secrets, err := client.AuthenticateDevice(context.Background(), defaultClientID, func(v rd.Verification) {
+		prompted = v
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "EBKEG2RR", prompted.UserCode)
+	assert.Equal(t, 2, polls)
+	assert.Equal(t, "ACCESS_TOKEN", token.AccessToken)
+	assert.Equal(t, rd.Secrets{ClientID: "0N2RHHK5OKNIX", ClientSecret: "135d1b6dc60dddbcaa2e5dc1772c85d56c5479ba"}, secrets)
+}
+
+func TestAuthClient_AuthenticateDeviceStopsOnFailure(t *testing.T) {
+	client := NewAuthTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/oauth/v2/device/code" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"device_code": "DEVICE_CODE", "user_code": "EBKEG2RR", "interval": 5, "expires_in": 600}`)),
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "wrong_parameter", "error_code": 2 }`)),
+			Header:     map[string][]string{"Content-Type": {"application/json"}},
+		}
+	})
+
+	_, _, err := client.AuthenticateDevice(context.Background(), defaultClientID, nil)
+	apiErr, ok := err.(rd.APIError)
+	assert.True(t, ok)
+	assert.Equal(t, rd.ErrBadParameterValue, apiErr.Code())
+}
+
+func TestAuthClient_AuthenticateDeviceExpires(t *testing.T) {
+	client := NewAuthTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/oauth/v2/device/code" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"device_code": "DEVICE_CODE", "user_code": "EBKEG2RR", "interval": 5, "expires_in": 1}`)),
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"client_id":null,"client_secret":null}`)),
+			Header:     map[string][]string{"Content-Type": {"application/json"}},
+		}
+	})
+
+	_, _, err := client.AuthenticateDevice(context.Background(), defaultClientID, nil)
+	assert.Equal(t, rd.ErrDeviceCodeExpired, err)
+}
+
+func TestAuthClient_RefreshUsesStoredClientCredentials(t *testing.T) {
+	client := NewAuthTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/oauth/v2/token", req.URL.String())
+		assert.Equal(t, "MY_CLIENT_ID", req.FormValue("client_id"))
+		assert.Equal(t, "MY_CLIENT_SECRET", req.FormValue("client_secret"))
+		assert.Equal(t, "REFRESH_TOKEN", req.FormValue("code"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"access_token": "NEW_TOKEN", "expires_in": 3600, "refresh_token": "NEW_REFRESH_TOKEN", "token_type": "Bearer" }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	token, err := client.RefreshAccessToken(rd.Token{RefreshToken: "REFRESH_TOKEN", ClientID: "MY_CLIENT_ID", ClientSecret: "MY_CLIENT_SECRET"})
+	assert.NoError(t, err)
+	assert.Equal(t, "NEW_TOKEN", token.AccessToken)
+	assert.Equal(t, "NEW_REFRESH_TOKEN", token.RefreshToken)
+	assert.Equal(t, "MY_CLIENT_ID", token.ClientID)
+	assert.Equal(t, "MY_CLIENT_SECRET", token.ClientSecret)
+}
+
+func TestAuthClient_ConfigurableBaseURL(t *testing.T) {
+	c := &http.Client{
+		Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			assert.Equal(t, "http://localhost:8080/oauth/device/code?client_id=X245A4XAIBGVM&new_credentials=yes", req.URL.String())
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"device_code": "DEVICE_CODE"}`)),
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		}),
+	}
+	client := rd.NewAuthClient(c, rd.AuthBaseURL("http://localhost:8080/oauth"))
+
+	verification, err := client.StartAuthentication(defaultClientID)
+	assert.NoError(t, err)
+	assert.Equal(t, "DEVICE_CODE", verification.DeviceCode)
 }
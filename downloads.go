@@ -1,15 +1,15 @@
 package rd
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 )
 
 // Endpoints
 const (
-	downloadsUrl      = apiBaseUrl + "/downloads"
-	downloadDeleteUrl = apiBaseUrl + "/downloads/delete/%s"
+	downloadsPath      = "/downloads"
+	downloadDeletePath = "/downloads/delete/%s"
 )
 
 type (
@@ -29,16 +29,38 @@ type (
 
 	DownloadService interface {
 		List() ([]DownloadInfo, error)
+		ListContext(ctx context.Context) ([]DownloadInfo, error)
+		ListPage(opts ListOptions) (DownloadsPage, error)
+		ListPageContext(ctx context.Context, opts ListOptions) (DownloadsPage, error)
+		All(ctx context.Context, opts ListOptions) *DownloadIterator
 		Delete(id string) error
+		DeleteContext(ctx context.Context, id string) error
 	}
 
 	DownloadClient struct {
 		HTTPDoer
 	}
+
+	DownloadsPage struct {
+		PaginatedResponse
+		Items []DownloadInfo
+	}
+
+	// DownloadIterator walks lazily through the downloads of the account, fetching one page at a time
+	DownloadIterator struct {
+		pager
+		client  *DownloadClient
+		items   []DownloadInfo
+		current DownloadInfo
+	}
 )
 
 func (s *DownloadClient) List() (items []DownloadInfo, err error) {
-	resp, err := httpGet(s.HTTPDoer, downloadsUrl)
+	return s.ListContext(context.Background())
+}
+
+func (s *DownloadClient) ListContext(ctx context.Context) (items []DownloadInfo, err error) {
+	resp, err := httpGet(ctx, s.HTTPDoer, apiURL(s.HTTPDoer, downloadsPath))
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +71,48 @@ func (s *DownloadClient) List() (items []DownloadInfo, err error) {
 	return items, err
 }
 
+// ListPage returns the page of downloads selected by the options, with the total count of downloads
+func (s *DownloadClient) ListPage(opts ListOptions) (page DownloadsPage, err error) {
+	return s.ListPageContext(context.Background(), opts)
+}
+
+func (s *DownloadClient) ListPageContext(ctx context.Context, opts ListOptions) (page DownloadsPage, err error) {
+	page.PaginatedResponse, err = getPage(ctx, s.HTTPDoer, downloadsPath, opts, &page.Items)
+	return page, err
+}
+
+// All iterates over all the downloads, starting at the offset of the options
+func (s *DownloadClient) All(ctx context.Context, opts ListOptions) *DownloadIterator {
+	return &DownloadIterator{pager: newPager(ctx, opts), client: s}
+}
+
+// Next advances to the next download, fetching the next page when needed. It returns false
+// when there are no more downloads or an error occurred, which is then returned by Err.
+func (it *DownloadIterator) Next() bool {
+	for len(it.items) == 0 {
+		if !it.more() {
+			return false
+		}
+
+		page, err := it.client.ListPageContext(it.ctx, it.opts)
+		it.items = page.Items
+		it.advance(page.PaginatedResponse, len(page.Items), err)
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Download returns the current download
+func (it *DownloadIterator) Download() DownloadInfo {
+	return it.current
+}
+
 func (s *DownloadClient) Delete(id string) error {
-	_, err := httpDelete(s.HTTPDoer, fmt.Sprintf(downloadDeleteUrl, id))
+	return s.DeleteContext(context.Background(), id)
+}
+
+func (s *DownloadClient) DeleteContext(ctx context.Context, id string) error {
+	_, err := httpDelete(ctx, s.HTTPDoer, apiURL(s.HTTPDoer, downloadDeletePath, id))
 	return err
 }
@@ -2,6 +2,7 @@ package rd_test
 
 import (
 	"bytes"
+	"context"
 	"github.com/nenad/rd"
 	"io/ioutil"
 	"net/http"
@@ -84,3 +85,74 @@ func TestClient_DeleteDownload(t *testing.T) {
 	err := client.Delete("XCBYL4ZIYPU42")
 	assert.NoError(t, err)
 }
+
+func TestClient_ListDownloadsPage(t *testing.T) {
+	client := NewDownloadsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/downloads?limit=50&offset=100", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`[ { "id": "EFX5LLAJYDR6B" } ]`)),
+			Header: map[string][]string{
+				"Content-Type":  {"application/json"},
+				"X-Total-Count": {"101"},
+			},
+		}
+	})
+
+	page, err := client.ListPage(rd.ListOptions{Offset: 100, Limit: 50})
+	assert.NoError(t, err)
+	assert.Equal(t, rd.DownloadsPage{
+		PaginatedResponse: rd.PaginatedResponse{Offset: 100, CountPerPage: 50, TotalCount: 101},
+		Items:             []rd.DownloadInfo{{ID: "EFX5LLAJYDR6B"}},
+	}, page)
+}
+
+func TestClient_AllDownloadsStopsOnEmptyPage(t *testing.T) {
+	requests := 0
+	client := NewDownloadsTestClient(func(req *http.Request) *http.Response {
+		requests++
+		if req.URL.Query().Get("offset") == "2" {
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header: map[string][]string{
+					"Content-Type": {"application/json"},
+				},
+			}
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`[ { "id": "1" }, { "id": "2" } ]`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	var ids []string
+	it := client.All(context.Background(), rd.ListOptions{Limit: 2})
+	for it.Next() {
+		ids = append(ids, it.Download().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "2"}, ids)
+	assert.Equal(t, 2, requests)
+}
+
+func TestClient_ListPageSendsPageInsteadOfOffset(t *testing.T) {
+	client := NewDownloadsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/downloads?limit=50&page=3", req.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`[]`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	_, err := client.ListPage(rd.ListOptions{Offset: 100, Page: 3, Limit: 50})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,142 @@
+package rd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ErrorCode is an error code returned by the RealDebrid API. Every APIError
+// matches the sentinel of its code when compared with errors.Is
+type ErrorCode int
+
+// Error codes returned by the API
+// RealDebrid API information: https://api.real-debrid.com/#api_error_codes
+const (
+	ErrInternal               ErrorCode = -1
+	ErrUnknown                ErrorCode = 0
+	ErrMissingParameter       ErrorCode = 1
+	ErrBadParameterValue      ErrorCode = 2
+	ErrUnknownMethod          ErrorCode = 3
+	ErrMethodNotAllowed       ErrorCode = 4
+	ErrSlowDown               ErrorCode = 5
+	ErrResourceUnreachable    ErrorCode = 6
+	ErrResourceNotFound       ErrorCode = 7
+	ErrBadToken               ErrorCode = 8
+	ErrPermissionDenied       ErrorCode = 9
+	ErrTwoFactorNeeded        ErrorCode = 10
+	ErrTwoFactorPending       ErrorCode = 11
+	ErrInvalidLogin           ErrorCode = 12
+	ErrInvalidPassword        ErrorCode = 13
+	ErrAccountLocked          ErrorCode = 14
+	ErrAccountNotActivated    ErrorCode = 15
+	ErrUnsupportedHoster      ErrorCode = 16
+	ErrHosterInMaintenance    ErrorCode = 17
+	ErrHosterLimitReached     ErrorCode = 18
+	ErrHosterUnavailable      ErrorCode = 19
+	ErrHosterPremiumOnly      ErrorCode = 20
+	ErrTooManyActiveDownloads ErrorCode = 21
+	ErrIPNotAllowed           ErrorCode = 22
+	ErrTrafficExhausted       ErrorCode = 23
+	ErrFileUnavailable        ErrorCode = 24
+	ErrServiceUnavailable     ErrorCode = 25
+	ErrUploadTooBig           ErrorCode = 26
+	ErrUploadError            ErrorCode = 27
+	ErrFileNotAllowed         ErrorCode = 28
+	ErrTorrentTooBig          ErrorCode = 29
+	ErrTorrentFileInvalid     ErrorCode = 30
+	ErrActionAlreadyDone      ErrorCode = 31
+	ErrImageResolution        ErrorCode = 32
+)
+
+var errorCodeDescription = map[ErrorCode]string{
+	ErrInternal:               "Internal error",
+	ErrUnknown:                "Unknown error",
+	ErrMissingParameter:       "Missing parameter",
+	ErrBadParameterValue:      "Bad parameter value",
+	ErrUnknownMethod:          "Unknown method",
+	ErrMethodNotAllowed:       "Method not allowed",
+	ErrSlowDown:               "Slow down",
+	ErrResourceUnreachable:    "Resource unreachable",
+	ErrResourceNotFound:       "Resource not found",
+	ErrBadToken:               "Bad token",
+	ErrPermissionDenied:       "Permission denied",
+	ErrTwoFactorNeeded:        "Two-Factor authentication needed",
+	ErrTwoFactorPending:       "Two-Factor authentication pending",
+	ErrInvalidLogin:           "Invalid login",
+	ErrInvalidPassword:        "Invalid password",
+	ErrAccountLocked:          "Account locked",
+	ErrAccountNotActivated:    "Account not activated",
+	ErrUnsupportedHoster:      "Unsupported hoster",
+	ErrHosterInMaintenance:    "Hoster in maintenance",
+	ErrHosterLimitReached:     "Hoster limit reached",
+	ErrHosterUnavailable:      "Hoster temporarily unavailable",
+	ErrHosterPremiumOnly:      "Hoster not available for free users",
+	ErrTooManyActiveDownloads: "Too many active downloads",
+	ErrIPNotAllowed:           "IP Address not allowed",
+	ErrTrafficExhausted:       "Traffic exhausted",
+	ErrFileUnavailable:        "File unavailable",
+	ErrServiceUnavailable:     "Service unavailable",
+	ErrUploadTooBig:           "Upload too big",
+	ErrUploadError:            "Upload error",
+	ErrFileNotAllowed:         "File not allowed",
+	ErrTorrentTooBig:          "Torrent too big",
+	ErrTorrentFileInvalid:     "Torrent file invalid",
+	ErrActionAlreadyDone:      "Action already done",
+	ErrImageResolution:        "Image resolution error",
+}
+
+func (c ErrorCode) Error() string {
+	if description, ok := errorCodeDescription[c]; ok {
+		return description
+	}
+	return fmt.Sprintf("error code %d", int(c))
+}
+
+// APIError is returned for every non-successful response of the API
+type APIError struct {
+	ErrorMessage string `json:"error"`
+	ErrorCode    int    `json:"error_code"`
+	ErrorDetails string `json:"error_details"`
+
+	StatusCode           int
+	ErrorCodeDescription string
+}
+
+func extractError(r *http.Response) APIError {
+	defer r.Body.Close()
+	e := &APIError{}
+	e.StatusCode = r.StatusCode
+	err := json.NewDecoder(r.Body).Decode(e)
+	if err != nil {
+		e.ErrorDetails = "decoding error"
+		e.ErrorMessage = err.Error()
+	}
+	e.ErrorCodeDescription = ErrorCode(e.ErrorCode).Error()
+
+	return *e
+}
+
+func (e APIError) Error() (msg string) {
+	msg += fmt.Sprintf("error_message: %s\n", e.ErrorMessage)
+	msg += fmt.Sprintf("error_code: %d - %s\n", e.ErrorCode, e.ErrorCodeDescription)
+	msg += fmt.Sprintf("error_details: %s\n", e.ErrorDetails)
+	msg += fmt.Sprintf("status_code: %d\n", e.StatusCode)
+	return msg
+}
+
+// Is reports whether the target is the ErrorCode sentinel of this error
+func (e APIError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && int(code) == e.ErrorCode
+}
+
+// Code returns the typed error code of the error
+func (e APIError) Code() ErrorCode {
+	return ErrorCode(e.ErrorCode)
+}
+
+func isErrorCode(err error, code ErrorCode) bool {
+	apiErr, ok := err.(APIError)
+	return ok && apiErr.Code() == code
+}
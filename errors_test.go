@@ -0,0 +1,50 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError_MatchesErrorCodeSentinel(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "too_big", "error_code": 29 }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	_, err := client.AddMagnetLinkSimple("magnet-url")
+	apiErr, ok := err.(rd.APIError)
+	assert.True(t, ok)
+	assert.True(t, apiErr.Is(rd.ErrTorrentTooBig))
+	assert.False(t, apiErr.Is(rd.ErrSlowDown))
+	assert.Equal(t, rd.ErrTorrentTooBig, apiErr.Code())
+	assert.Equal(t, "Torrent too big", apiErr.ErrorCodeDescription)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+}
+
+func TestAPIError_DecodingFailureIsUnknown(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`not json`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	_, err := client.GetTorrent("XCBYL4ZIYPU42")
+	apiErr, ok := err.(rd.APIError)
+	assert.True(t, ok)
+	assert.True(t, apiErr.Is(rd.ErrUnknown))
+}
@@ -0,0 +1,215 @@
+package rd
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Endpoints
+const (
+	hostsPath            = "/hosts"
+	hostsStatusPath      = "/hosts/status"
+	hostsRegexPath       = "/hosts/regex"
+	hostsRegexFolderPath = "/hosts/regexFolder"
+	hostsDomainsPath     = "/hosts/domains"
+)
+
+// Possible host states
+const (
+	HostUp          HostStatus = "up"
+	HostDown        HostStatus = "down"
+	HostUnsupported HostStatus = "unsupported"
+)
+
+type (
+	HostStatus string
+
+	Host struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Image string `json:"image"`
+	}
+
+	CompetitorStatus struct {
+		Status    HostStatus `json:"status"`
+		CheckTime time.Time  `json:"check_time"`
+	}
+
+	HostStatusInfo struct {
+		ID                string                      `json:"id"`
+		Name              string                      `json:"name"`
+		Image             string                      `json:"image"`
+		Supported         int                         `json:"supported"`
+		Status            HostStatus                  `json:"status"`
+		CheckTime         time.Time                   `json:"check_time"`
+		CompetitorsStatus map[string]CompetitorStatus `json:"competitors_status"`
+	}
+
+	// HostMatcher checks locally if links are supported, using the regular expressions of the API
+	HostMatcher struct {
+		links   []*regexp.Regexp
+		folders []*regexp.Regexp
+	}
+
+	HostsService interface {
+		List() (map[string]Host, error)
+		ListContext(ctx context.Context) (map[string]Host, error)
+		Status() (map[string]HostStatusInfo, error)
+		StatusContext(ctx context.Context) (map[string]HostStatusInfo, error)
+		Regex() ([]string, error)
+		RegexContext(ctx context.Context) ([]string, error)
+		RegexFolder() ([]string, error)
+		RegexFolderContext(ctx context.Context) ([]string, error)
+		Domains() ([]string, error)
+		DomainsContext(ctx context.Context) ([]string, error)
+		Matcher() (*HostMatcher, error)
+		MatcherContext(ctx context.Context) (*HostMatcher, error)
+	}
+
+	HostsClient struct {
+		HTTPDoer
+	}
+)
+
+// List returns the supported hosts, keyed by their main domain
+func (c *HostsClient) List() (map[string]Host, error) {
+	return c.ListContext(context.Background())
+}
+
+func (c *HostsClient) ListContext(ctx context.Context) (hosts map[string]Host, err error) {
+	err = c.get(ctx, hostsPath, &hosts)
+	return hosts, err
+}
+
+// Status returns the status of the hosts, keyed by their main domain
+func (c *HostsClient) Status() (map[string]HostStatusInfo, error) {
+	return c.StatusContext(context.Background())
+}
+
+func (c *HostsClient) StatusContext(ctx context.Context) (statuses map[string]HostStatusInfo, err error) {
+	err = c.get(ctx, hostsStatusPath, &statuses)
+	return statuses, err
+}
+
+// Regex returns the regular expressions matching the supported links
+func (c *HostsClient) Regex() ([]string, error) {
+	return c.RegexContext(context.Background())
+}
+
+func (c *HostsClient) RegexContext(ctx context.Context) (patterns []string, err error) {
+	err = c.get(ctx, hostsRegexPath, &patterns)
+	return patterns, err
+}
+
+// RegexFolder returns the regular expressions matching the supported folder links
+func (c *HostsClient) RegexFolder() ([]string, error) {
+	return c.RegexFolderContext(context.Background())
+}
+
+func (c *HostsClient) RegexFolderContext(ctx context.Context) (patterns []string, err error) {
+	err = c.get(ctx, hostsRegexFolderPath, &patterns)
+	return patterns, err
+}
+
+// Domains returns all the supported domains
+func (c *HostsClient) Domains() ([]string, error) {
+	return c.DomainsContext(context.Background())
+}
+
+func (c *HostsClient) DomainsContext(ctx context.Context) (domains []string, err error) {
+	err = c.get(ctx, hostsDomainsPath, &domains)
+	return domains, err
+}
+
+// Matcher fetches the link and folder regular expressions and compiles them into a HostMatcher
+func (c *HostsClient) Matcher() (*HostMatcher, error) {
+	return c.MatcherContext(context.Background())
+}
+
+func (c *HostsClient) MatcherContext(ctx context.Context) (*HostMatcher, error) {
+	links, err := c.RegexContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	folders, err := c.RegexFolderContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHostMatcher(links, folders), nil
+}
+
+func (c *HostsClient) get(ctx context.Context, path string, v interface{}) error {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, path))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// NewHostMatcher compiles the regular expressions returned by the API, which are written as
+// delimited JavaScript literals like /pattern/i. Patterns that can't be compiled by the regexp
+// package are skipped.
+func NewHostMatcher(linkPatterns, folderPatterns []string) *HostMatcher {
+	return &HostMatcher{
+		links:   compilePatterns(linkPatterns),
+		folders: compilePatterns(folderPatterns),
+	}
+}
+
+// IsSupported checks if the link can be unrestricted
+func (m *HostMatcher) IsSupported(link string) bool {
+	return matchAny(m.links, link)
+}
+
+// IsFolder checks if the link is a supported folder
+func (m *HostMatcher) IsFolder(link string) bool {
+	return matchAny(m.folders, link)
+}
+
+func matchAny(regexps []*regexp.Regexp, s string) bool {
+	for _, r := range regexps {
+		if r.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if r, err := regexp.Compile(convertPattern(pattern)); err == nil {
+			regexps = append(regexps, r)
+		}
+	}
+	return regexps
+}
+
+// convertPattern turns a delimited /pattern/flags literal into the regexp syntax
+func convertPattern(pattern string) string {
+	end := strings.LastIndex(pattern, "/")
+	if !strings.HasPrefix(pattern, "/") || end <= 0 {
+		return pattern
+	}
+
+	expr, flags := pattern[1:end], pattern[end+1:]
+	expr = strings.Replace(expr, `\/`, `/`, -1)
+
+	var supported string
+	for _, flag := range flags {
+		if strings.ContainsRune("ims", flag) {
+			supported += string(flag)
+		}
+	}
+	if supported != "" {
+		expr = "(?" + supported + ")" + expr
+	}
+	return expr
+}
@@ -0,0 +1,134 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewHostsTestClient(fn TestRoundTripFunc) rd.HostsService {
+	c := &http.Client{
+		Transport: fn,
+	}
+	return rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c).Hosts
+}
+
+func TestClient_ListHosts(t *testing.T) {
+	client := NewHostsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/hosts", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(
+				`{ "1fichier.com": { "id": "1F", "name": "1fichier", "image": "https://fcdn.real-debrid.com/0754/images/hosters/1fichier.png" } }`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	hosts, err := client.List()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]rd.Host{
+		"1fichier.com": {ID: "1F", Name: "1fichier", Image: "https://fcdn.real-debrid.com/0754/images/hosters/1fichier.png"},
+	}, hosts)
+}
+
+func TestClient_HostsStatus(t *testing.T) {
+	client := NewHostsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/hosts/status", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{
+    "1fichier.com": {
+        "id": "1F",
+        "name": "1fichier",
+        "image": "https://fcdn.real-debrid.com/0754/images/hosters/1fichier.png",
+        "supported": 1,
+        "status": "up",
+        "check_time": "2019-01-20T10:31:01.000Z",
+        "competitors_status": { "alldebrid.com": { "status": "down", "check_time": "2019-01-20T10:30:01.000Z" } }
+    }
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	statuses, err := client.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]rd.HostStatusInfo{
+		"1fichier.com": {
+			ID:        "1F",
+			Name:      "1fichier",
+			Image:     "https://fcdn.real-debrid.com/0754/images/hosters/1fichier.png",
+			Supported: 1,
+			Status:    rd.HostUp,
+			CheckTime: time.Date(2019, 1, 20, 10, 31, 1, 0, time.UTC),
+			CompetitorsStatus: map[string]rd.CompetitorStatus{
+				"alldebrid.com": {Status: rd.HostDown, CheckTime: time.Date(2019, 1, 20, 10, 30, 1, 0, time.UTC)},
+			},
+		},
+	}, statuses)
+}
+
+func TestClient_HostsDomains(t *testing.T) {
+	client := NewHostsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/hosts/domains", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`["1fichier.com", "uptobox.com"]`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	domains, err := client.Domains()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1fichier.com", "uptobox.com"}, domains)
+}
+
+func TestClient_HostsMatcher(t *testing.T) {
+	client := NewHostsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "GET", req.Method)
+
+		body := `["/(https?:\\/\\/)?(www\\.)?UPTOBOX\\.com\\/[a-z0-9]{12}/i", "/(?=lookahead)/"]`
+		if req.URL.Path == "/rest/1.0/hosts/regexFolder" {
+			body = `["/https?:\\/\\/(www\\.)?1fichier\\.com\\/dir\\/[a-z0-9]+/"]`
+		} else {
+			assert.Equal(t, "/rest/1.0/hosts/regex", req.URL.Path)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	matcher, err := client.Matcher()
+	assert.NoError(t, err)
+	assert.True(t, matcher.IsSupported("https://uptobox.com/abcdef123456"))
+	assert.False(t, matcher.IsSupported("https://example.com/abcdef123456"))
+	assert.True(t, matcher.IsFolder("https://1fichier.com/dir/abc123"))
+	assert.False(t, matcher.IsFolder("https://uptobox.com/abcdef123456"))
+}
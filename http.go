@@ -2,64 +2,185 @@ package rd
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 )
 
 type (
-	PaginatedResponse struct {
-		Page         int
-		Offset       int
-		CountPerPage int
-		TotalCount   int
-		Items        []interface{}
-	}
-
 	HTTPDoer interface {
 		Do(r *http.Request) (*http.Response, error)
 	}
 
 	HTTPClient struct {
 		client    HTTPDoer
+		baseURL   string
 		token     Token
+		tokenMu   sync.RWMutex
+		private   bool
+		disabled  bool
 		refresher TokenRefresher
+		refreshMu sync.Mutex
+		retry     RetryPolicy
+		limiter   *RateLimiter
+		clock     *Clock
+
+		authBaseURL    string
+		onTokenRefresh []func(Token) error
 	}
 )
 
 func (c *HTTPClient) Do(r *http.Request) (resp *http.Response, err error) {
-	if c.refresher != nil && !c.token.IsValid() {
-		if err := c.refreshToken(); err != nil {
+	for attempt := 1; ; attempt++ {
+		var sent bool
+		resp, sent, err = c.do(r)
+		if c.retry == nil || !sent {
+			return resp, err
+		}
+
+		delay, retry := c.retry.Retry(attempt, r, resp, err)
+		if !retry || !rewindBody(r) {
+			return resp, err
+		}
+
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+
+		if err := sleepContext(r.Context(), delay); err != nil {
 			return nil, err
 		}
 	}
+}
 
-	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token.AccessToken))
+// do sends the request with a valid token. Unless sent is set, the request failed locally, for example
+// because the token is disabled or couldn't be refreshed, so it must not be retried.
+func (c *HTTPClient) do(r *http.Request) (resp *http.Response, sent bool, err error) {
+	token, err := c.activeToken()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if c.refresher != nil && !token.IsValidWith(c.clock) {
+		if token, err = c.refreshToken(r.Context(), token); err != nil {
+			return nil, false, err
+		}
+	}
+
+	resp, sent, err = c.send(r, token)
+	if c.refresher == nil || !isErrorCode(err, ErrBadToken) || !rewindBody(r) {
+		return resp, sent, err
+	}
+
+	// The API rejected a token that looked valid, so refresh it and replay the request once
+	if token, err = c.refreshToken(r.Context(), token); err != nil {
+		return nil, false, err
+	}
+
+	return c.send(r, token)
+}
+
+func (c *HTTPClient) send(r *http.Request, token Token) (resp *http.Response, sent bool, err error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(r.Context()); err != nil {
+			return nil, false, err
+		}
+	}
+
+	r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
 
 	resp, err = c.client.Do(r)
 	if err != nil {
-		return resp, err
+		return resp, true, err
 	}
 
-	return resp, parseErrorResponse(resp)
+	return resp, true, parseErrorResponse(resp)
 }
 
 func AutoRefresh(c *HTTPClient) {
-	c.refresher = NewAuthClient(c.client)
+	c.refresher = NewAuthClient(c.client, AuthClock(c.clock))
 }
 
-func (c *HTTPClient) refreshToken() error {
-	token, err := c.refresher.RefreshAccessToken(c.token)
+func (c *HTTPClient) currentToken() Token {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.token
+}
+
+// activeToken returns the current token, unless it was disabled
+func (c *HTTPClient) activeToken() (Token, error) {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	if c.disabled {
+		return Token{}, ErrTokenDisabled
+	}
+	return c.token, nil
+}
+
+// refreshToken replaces the stale token with a new one. Concurrent callers wait for a single
+// refresh, and callers holding an already replaced token receive the current one instead.
+func (c *HTTPClient) refreshToken(ctx context.Context, stale Token) (Token, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	current, err := c.activeToken()
+	if err != nil || current.AccessToken != stale.AccessToken {
+		return current, err
+	}
+
+	token, err := c.refresher.RefreshAccessTokenContext(ctx, current)
 	if err != nil {
-		return err
+		return token, err
 	}
+
+	return token, c.swapToken(token, false)
+}
+
+// pinClockOffset records a zero offset in a token obtained with an unknown one, assuming the local
+// clock was right back then, so the drift measured since is corrected
+func (c *HTTPClient) pinClockOffset() error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	token, err := c.activeToken()
+	if err != nil || c.private || token.AccessToken == "" || token.ClockOffset != nil {
+		return nil
+	}
+
+	offset := time.Duration(0)
+	token.ClockOffset = &offset
+	return c.swapToken(token, false)
+}
+
+// disableToken clears the token for good, so it isn't refreshed anymore
+func (c *HTTPClient) disableToken() error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	return c.swapToken(Token{}, true)
+}
+
+// swapToken replaces the token and notifies the hooks about it
+func (c *HTTPClient) swapToken(token Token, disabled bool) error {
+	c.tokenMu.Lock()
 	c.token = token
+	c.disabled = disabled
+	c.tokenMu.Unlock()
+
+	for _, fn := range c.onTokenRefresh {
+		if err := fn(token); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func httpPostForm(doer HTTPDoer, url string, values map[string]string) (resp *http.Response, err error) {
+func httpPostForm(ctx context.Context, doer HTTPDoer, url string, values map[string]string) (resp *http.Response, err error) {
 	formBytes := &bytes.Buffer{}
 	writer := multipart.NewWriter(formBytes)
 	_ = writer.SetBoundary("realdebrid-boundary")
@@ -77,13 +198,17 @@ func httpPostForm(doer HTTPDoer, url string, values map[string]string) (resp *ht
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 	req.Header.Add("Content-Type", writer.FormDataContentType())
 
 	resp, err = doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
 	return resp, parseErrorResponse(resp)
 }
 
-func httpGet(doer HTTPDoer, path string, params ...map[string]string) (resp *http.Response, err error) {
+func httpGet(ctx context.Context, doer HTTPDoer, path string, params ...map[string]string) (resp *http.Response, err error) {
 	u, err := url.Parse(path)
 	if err != nil {
 		return nil, err
@@ -102,6 +227,36 @@ func httpGet(doer HTTPDoer, path string, params ...map[string]string) (resp *htt
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+
+	resp, err = doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, parseErrorResponse(resp)
+}
+
+func httpPut(ctx context.Context, doer HTTPDoer, path string, body io.Reader, params ...map[string]string) (resp *http.Response, err error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	for _, p := range params {
+		for k, v := range p {
+			query.Add(k, v)
+		}
+	}
+
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("PUT", u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Content-Type", "application/octet-stream")
 
 	resp, err = doer.Do(req)
 	if err != nil {
@@ -110,7 +265,7 @@ func httpGet(doer HTTPDoer, path string, params ...map[string]string) (resp *htt
 	return resp, parseErrorResponse(resp)
 }
 
-func httpDelete(doer HTTPDoer, path string) (resp *http.Response, err error) {
+func httpDelete(ctx context.Context, doer HTTPDoer, path string) (resp *http.Response, err error) {
 	u, err := url.Parse(path)
 	if err != nil {
 		return nil, err
@@ -120,6 +275,7 @@ func httpDelete(doer HTTPDoer, path string) (resp *http.Response, err error) {
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	resp, err = doer.Do(req)
 	if err != nil {
@@ -144,71 +300,3 @@ func parseErrorResponse(r *http.Response) error {
 
 	return extractError(r)
 }
-
-var errorCodeDescription = map[int]string{
-	-1: "Internal error",
-	0:  "Unknown error",
-	1:  "Missing parameter",
-	2:  "Bad parameter value",
-	3:  "Unknown method",
-	4:  "Method not allowed",
-	5:  "Slow down",
-	6:  "Resource unreachable",
-	7:  "Resource not found",
-	8:  "Bad token",
-	9:  "Permission denied",
-	10: "Two-Factor authentication needed",
-	11: "Two-Factor authentication pending",
-	12: "Invalid login",
-	13: "Invalid password",
-	14: "Account locked",
-	15: "Account not activated",
-	16: "Unsupported hoster",
-	17: "Hoster in maintenance",
-	18: "Hoster limit reached",
-	19: "Hoster temporarily unavailable",
-	20: "Hoster not available for free users",
-	21: "Too many active downloads",
-	22: "IP Address not allowed",
-	23: "Traffic exhausted",
-	24: "File unavailable",
-	25: "Service unavailable",
-	26: "Upload too big",
-	27: "Upload error",
-	28: "File not allowed",
-	29: "Torrent too big",
-	30: "Torrent file invalid",
-	31: "Action already done",
-	32: "Image resolution error",
-}
-
-type httpError struct {
-	ErrorMessage string `json:"error"`
-	ErrorCode    int    `json:"error_code"`
-	ErrorDetails string `json:"error_details"`
-
-	StatusCode           int
-	ErrorCodeDescription string
-}
-
-func extractError(r *http.Response) httpError {
-	defer r.Body.Close()
-	e := &httpError{}
-	e.StatusCode = r.StatusCode
-	e.ErrorCodeDescription = errorCodeDescription[e.ErrorCode]
-	err := json.NewDecoder(r.Body).Decode(e)
-	if err != nil {
-		e.ErrorDetails = "decoding error"
-		e.ErrorMessage = err.Error()
-	}
-
-	return *e
-}
-
-func (e httpError) Error() (msg string) {
-	msg += fmt.Sprintf("error_message: %s\n", e.ErrorMessage)
-	msg += fmt.Sprintf("error_code: %d - %s\n", e.ErrorCode, e.ErrorCodeDescription)
-	msg += fmt.Sprintf("error_details: %s\n", e.ErrorDetails)
-	msg += fmt.Sprintf("status_code: %d\n", e.StatusCode)
-	return msg
-}
@@ -1,9 +1,14 @@
 package rd
 
 import (
+	"bytes"
+	"context"
 	"io/ioutil"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -52,6 +57,136 @@ func Test_FormPost(t *testing.T) {
 		}
 	})
 
-	_, err := httpPostForm(client, "https://example.com", map[string]string{"hello": "world"})
+	_, err := httpPostForm(context.Background(), client, "https://example.com", map[string]string{"hello": "world"})
+	assert.NoError(t, err)
+}
+
+type contextKey string
+
+type testRefresher func(ctx context.Context, token Token) (Token, error)
+
+func (r testRefresher) RefreshAccessToken(token Token) (Token, error) {
+	return r(context.Background(), token)
+}
+
+func (r testRefresher) RefreshAccessTokenContext(ctx context.Context, token Token) (Token, error) {
+	return r(ctx, token)
+}
+
+func Test_ContextIsPropagated(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "value", req.Context().Value(contextKey("key")))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     map[string][]string{"Content-Type": {"application/json"}},
+		}
+	})
+
+	_, err := httpGet(ctx, client, "https://example.com")
+	assert.NoError(t, err)
+	_, err = httpPostForm(ctx, client, "https://example.com", map[string]string{"hello": "world"})
+	assert.NoError(t, err)
+	_, err = httpDelete(ctx, client, "https://example.com")
+	assert.NoError(t, err)
+}
+
+func Test_ContextIsPropagatedToTokenRefresh(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "Bearer NEW_TOKEN", req.Header.Get("Authorization"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     map[string][]string{"Content-Type": {"application/json"}},
+		}
+	})
+	client.refresher = testRefresher(func(ctx context.Context, token Token) (Token, error) {
+		assert.Equal(t, "value", ctx.Value(contextKey("key")))
+		return Token{AccessToken: "NEW_TOKEN", ExpiresIn: 3600, ObtainedAt: time.Now()}, nil
+	})
+
+	_, err := httpGet(ctx, client, "https://example.com")
+	assert.NoError(t, err)
+}
+
+func Test_ConcurrentRefreshIsSingleFlighted(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "Bearer NEW_TOKEN", req.Header.Get("Authorization"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     map[string][]string{"Content-Type": {"application/json"}},
+		}
+	})
+
+	var refreshes int32
+	client.refresher = testRefresher(func(ctx context.Context, token Token) (Token, error) {
+		atomic.AddInt32(&refreshes, 1)
+		time.Sleep(10 * time.Millisecond)
+		return Token{AccessToken: "NEW_TOKEN", ExpiresIn: 3600, ObtainedAt: time.Now()}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := httpGet(context.Background(), client, "https://example.com")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), refreshes)
+}
+
+func Test_BadTokenIsRefreshedAndReplayed(t *testing.T) {
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		assert.Equal(t, "world", req.FormValue("hello"))
+		if req.Header.Get("Authorization") == "Bearer VALID_TOKEN" {
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "bad_token", "error_code": 8 }`)),
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     map[string][]string{"Content-Type": {"application/json"}},
+		}
+	})
+	client.token.ObtainedAt = time.Now()
+	client.refresher = testRefresher(func(ctx context.Context, token Token) (Token, error) {
+		assert.Equal(t, "VALID_TOKEN", token.AccessToken)
+		return Token{AccessToken: "NEW_TOKEN", ExpiresIn: 3600, ObtainedAt: time.Now()}, nil
+	})
+
+	_, err := httpPostForm(context.Background(), client, "https://example.com", map[string]string{"hello": "world"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "NEW_TOKEN", client.currentToken().AccessToken)
+}
+
+func Test_TokenObtainedWithClockOffsetIsNotRefreshed(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "Bearer VALID_TOKEN", req.Header.Get("Authorization"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     map[string][]string{"Content-Type": {"application/json"}},
+		}
+	})
+	client.clock = &Clock{}
+	client.clock.setOffset(time.Hour)
+	client.token.ObtainedAt = time.Now()
+	offset := time.Hour
+	client.token.ClockOffset = &offset
+	client.refresher = testRefresher(func(ctx context.Context, token Token) (Token, error) {
+		t.Error("token should not be refreshed")
+		return token, nil
+	})
+
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	_, err := client.Do(req)
 	assert.NoError(t, err)
 }
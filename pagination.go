@@ -0,0 +1,108 @@
+package rd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const defaultPageLimit = 100
+
+// FilterActive lists only the active torrents
+const FilterActive = "active"
+
+type (
+	// ListOptions selects the page of a listing. Page takes precedence over Offset when both are set,
+	// so Offset is not sent then.
+	ListOptions struct {
+		Offset int
+		Page   int
+		Limit  int
+		// Filter narrows down the listing, only FilterActive is supported for torrents
+		Filter string
+	}
+
+	// PaginatedResponse describes the page of a listing and the total count of its items
+	PaginatedResponse struct {
+		Page         int
+		Offset       int
+		CountPerPage int
+		TotalCount   int
+	}
+
+	// pager keeps track of the offset while walking through the pages of a listing
+	pager struct {
+		ctx  context.Context
+		opts ListOptions
+		done bool
+		err  error
+	}
+)
+
+func (o ListOptions) params() map[string]string {
+	params := map[string]string{}
+	if o.Page > 0 {
+		params["page"] = strconv.Itoa(o.Page)
+	} else if o.Offset > 0 {
+		params["offset"] = strconv.Itoa(o.Offset)
+	}
+	if o.Limit > 0 {
+		params["limit"] = strconv.Itoa(o.Limit)
+	}
+	if o.Filter != "" {
+		params["filter"] = o.Filter
+	}
+	return params
+}
+
+// getPage fetches a page of the listing into items, reading the total count from the X-Total-Count header
+func getPage(ctx context.Context, doer HTTPDoer, path string, opts ListOptions, items interface{}) (page PaginatedResponse, err error) {
+	resp, err := httpGet(ctx, doer, apiURL(doer, path), opts.params())
+	if err != nil {
+		return page, err
+	}
+
+	defer resp.Body.Close()
+	page = PaginatedResponse{Page: opts.Page, Offset: opts.Offset, CountPerPage: opts.Limit}
+	page.TotalCount, _ = strconv.Atoi(resp.Header.Get("X-Total-Count"))
+
+	// Pages past the end of the listing have no content
+	if resp.StatusCode == http.StatusNoContent {
+		return page, nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(items)
+	return page, err
+}
+
+func newPager(ctx context.Context, opts ListOptions) pager {
+	opts.Page = 0
+	if opts.Limit <= 0 {
+		opts.Limit = defaultPageLimit
+	}
+	return pager{ctx: ctx, opts: opts}
+}
+
+func (p *pager) more() bool {
+	return !p.done && p.err == nil
+}
+
+// advance moves the offset past the fetched page, stopping at the end of the listing. A page
+// shorter than the limit doesn't mark the end, as the server may cap the number of items per page.
+func (p *pager) advance(page PaginatedResponse, n int, err error) {
+	if err != nil {
+		p.err = err
+		return
+	}
+
+	p.opts.Offset += n
+	if n == 0 || (page.TotalCount > 0 && p.opts.Offset >= page.TotalCount) {
+		p.done = true
+	}
+}
+
+// Err returns the error that stopped the iteration, if any
+func (p *pager) Err() error {
+	return p.err
+}
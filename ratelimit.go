@@ -0,0 +1,99 @@
+package rd
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RealDebrid allows up to 250 requests per minute
+const (
+	defaultRateLimitRequests = 250
+	defaultRateLimitPeriod   = time.Minute
+)
+
+// RateLimiter is a token bucket limiting the number of requests sent to the API.
+// It is safe for concurrent use, so one limiter can be shared between clients.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing the given number of requests per period,
+// which can all be sent at once when the bucket is full
+func NewRateLimiter(requests int, per time.Duration) *RateLimiter {
+	return &RateLimiter{
+		rate:   float64(requests) / per.Seconds(),
+		burst:  float64(requests),
+		tokens: float64(requests),
+		last:   time.Now(),
+	}
+}
+
+// RateLimit limits the client to the request quota of RealDebrid
+func RateLimit(c *HTTPClient) {
+	c.limiter = NewRateLimiter(defaultRateLimitRequests, defaultRateLimitPeriod)
+}
+
+// WithRateLimiter limits the client with the given limiter
+func WithRateLimiter(limiter *RateLimiter) func(*HTTPClient) {
+	return func(c *HTTPClient) {
+		c.limiter = limiter
+	}
+}
+
+// Wait blocks until a request can be sent or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.advance(time.Now())
+	l.tokens--
+	delay := l.delay()
+	l.mu.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		// The request won't be sent, so hand back its reservation
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// Delay returns how long the next request would currently have to wait
+func (l *RateLimiter) Delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(time.Now())
+	if l.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// advance refills the bucket with the tokens accumulated since the last call
+func (l *RateLimiter) advance(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	l.last = now
+	l.tokens += elapsed * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// delay returns the time until the token balance is not negative anymore
+func (l *RateLimiter) delay() time.Duration {
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
@@ -0,0 +1,68 @@
+package rd_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_AllowsBurstThenWaits(t *testing.T) {
+	limiter := rd.NewRateLimiter(2, time.Minute)
+
+	assert.Equal(t, time.Duration(0), limiter.Delay())
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	delay := limiter.Delay()
+	assert.True(t, delay > 25*time.Second && delay <= 30*time.Second, "unexpected delay %s", delay)
+}
+
+func TestRateLimiter_CancelledWaitReturnsReservation(t *testing.T) {
+	limiter := rd.NewRateLimiter(1, time.Minute)
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx))
+
+	delay := limiter.Delay()
+	assert.True(t, delay > 55*time.Second && delay <= time.Minute, "unexpected delay %s", delay)
+}
+
+func TestRateLimiter_IsSharedBetweenServices(t *testing.T) {
+	c := &http.Client{
+		Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header: map[string][]string{
+					"Content-Type": {"application/json"},
+				},
+			}
+		}),
+	}
+	client := rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c, rd.WithRateLimiter(rd.NewRateLimiter(4, time.Minute)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, client.Torrents.Delete("XCBYL4ZIYPU42"))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, client.Downloads.Delete("XCBYL4ZIYPU42"))
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, client.RateLimitDelay() > 0)
+}
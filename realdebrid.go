@@ -1,15 +1,34 @@
 package rd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 const apiBaseUrl = "https://api.real-debrid.com/rest/1.0"
 
+// Endpoints
+const (
+	disableAccessTokenPath = "/disable_access_token"
+)
+
+// ErrTokenDisabled is returned for requests sent after logging out
+var ErrTokenDisabled = errors.New("access token was disabled")
+
 type RealDebrid struct {
 	Torrents   TorrentService
 	Unrestrict UnrestrictService
 	Downloads  DownloadService
+	User       UserService
+	Traffic    TrafficService
+	Hosts      HostsService
+	Streaming  StreamingService
+	Settings   SettingsService
+	Time       TimeService
 
 	httpClient *HTTPClient
 }
@@ -19,20 +38,121 @@ func NewRealDebrid(token Token, client *http.Client, options ...func(*HTTPClient
 		client = http.DefaultClient
 	}
 
-	c := &HTTPClient{client: client, token: token}
+	c := &HTTPClient{client: client, token: token, clock: &Clock{}}
 
 	for _, option := range options {
 		option(c)
 	}
 
+	if auth, ok := c.refresher.(*AuthClient); ok && c.authBaseURL != "" {
+		AuthBaseURL(c.authBaseURL)(auth)
+	}
+
 	return &RealDebrid{
 		httpClient: c,
 		Torrents:   &TorrentClient{c},
 		Unrestrict: &UnrestrictClient{c},
 		Downloads:  &DownloadClient{c},
+		User:       &UserClient{c},
+		Traffic:    &TrafficClient{c},
+		Hosts:      &HostsClient{c},
+		Streaming:  &StreamingClient{c},
+		Settings:   &SettingsClient{c},
+		Time:       &TimeClient{c},
+	}
+}
+
+// NewRealDebridWithAPIToken creates a client authenticated with the private API token of the
+// account, found at https://real-debrid.com/apitoken. That token never expires, so it is never
+// refreshed, even with the AutoRefresh option.
+func NewRealDebridWithAPIToken(apiToken string, client *http.Client, options ...func(*HTTPClient)) *RealDebrid {
+	c := NewRealDebrid(Token{AccessToken: apiToken, TokenType: "Bearer"}, client, options...)
+	c.httpClient.refresher = nil
+	c.httpClient.private = true
+	return c
+}
+
+// WithBaseURL makes the client send its requests to the given REST base URL instead of the RealDebrid one
+func WithBaseURL(url string) func(*HTTPClient) {
+	return func(c *HTTPClient) {
+		c.baseURL = strings.TrimRight(url, "/")
+	}
+}
+
+// WithAuthBaseURL makes the client refresh its token at the given OAuth base URL instead of the RealDebrid one
+func WithAuthBaseURL(url string) func(*HTTPClient) {
+	return func(c *HTTPClient) {
+		c.authBaseURL = strings.TrimRight(url, "/")
+	}
+}
+
+// apiURL builds the URL of the endpoint from the base URL the doer is configured with
+func apiURL(doer HTTPDoer, path string, args ...interface{}) string {
+	if len(args) > 0 {
+		path = fmt.Sprintf(path, args...)
+	}
+
+	if c, ok := doer.(*HTTPClient); ok && c.baseURL != "" {
+		return c.baseURL + path
 	}
+	return apiBaseUrl + path
+}
+
+// Token returns the token currently used by the client, which changes when it gets refreshed
+func (c *RealDebrid) Token() Token {
+	return c.httpClient.currentToken()
 }
 
 func (c *RealDebrid) IsTokenValid() bool {
-	return c.httpClient.token.IsValid()
+	token := c.httpClient.currentToken()
+	if c.httpClient.private {
+		return token.AccessToken != ""
+	}
+	return token.IsValidWith(c.httpClient.clock)
+}
+
+// Logout disables the access token, so it can't be used anymore. The client then drops the token
+// and fails every further request with ErrTokenDisabled right away, even with a retry policy, while
+// the token refresh hooks are called with an empty token so stored credentials get wiped.
+func (c *RealDebrid) Logout() error {
+	return c.LogoutContext(context.Background())
+}
+
+func (c *RealDebrid) LogoutContext(ctx context.Context) error {
+	if _, err := httpGet(ctx, c.httpClient, apiURL(c.httpClient, disableAccessTokenPath)); err != nil {
+		return err
+	}
+
+	return c.httpClient.disableToken()
+}
+
+// RateLimitDelay returns how long the next request has to wait for the rate limiter
+func (c *RealDebrid) RateLimitDelay() time.Duration {
+	if c.httpClient.limiter == nil {
+		return 0
+	}
+	return c.httpClient.limiter.Delay()
+}
+
+// SyncClock measures the offset between the local time and the server time. The offset then
+// corrects the time used for token expiry, which can be read with Now. A token obtained with an
+// unknown offset is assumed to have been obtained while the local clock was right.
+func (c *RealDebrid) SyncClock(ctx context.Context) error {
+	before := time.Now()
+	server, err := c.Time.ISOContext(ctx)
+	if err != nil {
+		return err
+	}
+	after := time.Now()
+
+	// The server time is taken somewhere during the request, so assume it's halfway through
+	local := before.Add(after.Sub(before) / 2)
+	c.httpClient.clock.setOffset(server.Sub(local))
+	return c.httpClient.pinClockOffset()
+}
+
+// Now returns the current time corrected by the offset measured with SyncClock, so it can be
+// compared with timestamps returned by the API, like DownloadInfo.Generated
+func (c *RealDebrid) Now() time.Time {
+	return c.httpClient.clock.Now()
 }
@@ -0,0 +1,112 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealDebrid_APITokenNeverExpires(t *testing.T) {
+	c := &http.Client{
+		Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents/delete/XCBYL4ZIYPU42", req.URL.String())
+			assert.Equal(t, "Bearer PRIVATE_TOKEN", req.Header.Get("Authorization"))
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header: map[string][]string{
+					"Content-Type": {"application/json"},
+				},
+			}
+		}),
+	}
+
+	client := rd.NewRealDebridWithAPIToken("PRIVATE_TOKEN", c, rd.AutoRefresh)
+	assert.True(t, client.IsTokenValid())
+	assert.NoError(t, client.Torrents.Delete("XCBYL4ZIYPU42"))
+	assert.Equal(t, "PRIVATE_TOKEN", client.Token().AccessToken)
+}
+
+func TestRealDebrid_ConfigurableBaseURLs(t *testing.T) {
+	var urls []string
+	c := &http.Client{
+		Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			urls = append(urls, req.URL.String())
+			switch req.URL.Path {
+			case "/oauth/device/credentials":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"client_id":"0N2RHHK5OKNIX","client_secret":"135d1b6dc60dddbcaa2e5dc1772c85d56c5479ba"}`)),
+					Header:     map[string][]string{"Content-Type": {"application/json"}},
+				}
+			case "/oauth/token":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"access_token": "NEW_TOKEN", "expires_in": 3600, "refresh_token": "NEW_REFRESH_TOKEN", "token_type": "Bearer" }`)),
+					Header:     map[string][]string{"Content-Type": {"application/json"}},
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		}),
+	}
+
+	client := rd.NewRealDebrid(
+		rd.Token{AccessToken: "EXPIRED_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c, rd.AutoRefresh, rd.WithBaseURL("http://localhost:8080/rest/"), rd.WithAuthBaseURL("http://localhost:8080/oauth"))
+
+	assert.NoError(t, client.Downloads.Delete("XCBYL4ZIYPU42"))
+	assert.Equal(t, []string{
+		"http://localhost:8080/oauth/device/credentials?client_id=X245A4XAIBGVM&code=REFRESH_TOKEN",
+		"http://localhost:8080/oauth/token",
+		"http://localhost:8080/rest/downloads/delete/XCBYL4ZIYPU42",
+	}, urls)
+}
+
+func TestRealDebrid_LogoutDisablesToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := rd.NewFileTokenStore(filepath.Join(dir, "token.json"))
+	token := rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN", ObtainedAt: time.Now()}
+	assert.NoError(t, store.Save(token))
+
+	requests := 0
+	c := &http.Client{
+		Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			requests++
+			assert.Equal(t, "https://api.real-debrid.com/rest/1.0/disable_access_token", req.URL.String())
+			assert.Equal(t, "GET", req.Method)
+			assert.Equal(t, "Bearer VALID_TOKEN", req.Header.Get("Authorization"))
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header: map[string][]string{
+					"Content-Type": {"application/json"},
+				},
+			}
+		}),
+	}
+
+	client := rd.NewRealDebrid(token, c, rd.AutoRefresh, rd.WithTokenStore(store), rd.WithRetryPolicy(rd.DefaultRetryPolicy))
+	assert.NoError(t, client.Logout())
+	assert.Equal(t, rd.Token{}, client.Token())
+	assert.False(t, client.IsTokenValid())
+
+	_, err = store.Load()
+	assert.True(t, os.IsNotExist(err))
+
+	start := time.Now()
+	assert.Equal(t, rd.ErrTokenDisabled, client.Torrents.Delete("XCBYL4ZIYPU42"))
+	assert.True(t, time.Since(start) < 100*time.Millisecond, "failed after %s", time.Since(start))
+	assert.Equal(t, 1, requests)
+}
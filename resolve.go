@@ -0,0 +1,131 @@
+package rd
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Bounds the deletion of a torrent that failed to resolve, as the context of the request can't be used for it
+const resolveCleanupTimeout = 30 * time.Second
+
+type (
+	// FileSelector picks the IDs of the files to download from the files of a torrent
+	FileSelector func(files []File) []int
+
+	ResolveOptions struct {
+		// Host is the hoster domain the torrent is downloaded to, the API picks one if empty
+		Host string
+		// Wait configures the polling of the torrent, its OnUpdate reports the progress
+		Wait WaitOptions
+		// Unrestrict is used for unrestricting the links of the downloaded torrent
+		Unrestrict UnrestrictOptions
+		// DeleteOnFailure deletes the torrent from the account if resolving it fails before it is downloaded
+		DeleteOnFailure bool
+	}
+
+	// ResolvedFile is a selected file of the torrent with its unrestricted link. Err is set
+	// instead of Info when unrestricting the link failed. File is empty if the link is packed.
+	ResolvedFile struct {
+		File File
+		Link string
+		Info UnrestrictInfo
+		Err  error
+	}
+
+	ResolveResult struct {
+		TorrentID string
+		Files     []ResolvedFile
+		// Packed is set when the API packed the selected files into archives, so the links can't be
+		// mapped to the files of the torrent
+		Packed bool
+	}
+)
+
+// SelectAllFiles selects every file of the torrent
+func SelectAllFiles(files []File) []int {
+	ids := make([]int, len(files))
+	for i, f := range files {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+// Resolve turns a magnet link into direct download links, using the default options
+func (c *RealDebrid) Resolve(ctx context.Context, magnet string, selector FileSelector) (result ResolveResult, err error) {
+	return c.ResolveWithOptions(ctx, magnet, selector, ResolveOptions{})
+}
+
+// ResolveWithOptions turns a magnet link into direct download links. It adds the magnet, selects
+// the files picked by the selector, or all of them if it is nil, waits for the torrent to be
+// downloaded and unrestricts the link of every selected file. If the API packed the files into
+// archives, the links are returned without their files and the result is marked as Packed.
+func (c *RealDebrid) ResolveWithOptions(ctx context.Context, magnet string, selector FileSelector, opts ResolveOptions) (result ResolveResult, err error) {
+	if selector == nil {
+		selector = SelectAllFiles
+	}
+
+	added, err := c.Torrents.AddMagnetLinkContext(ctx, magnet, AddTorrentOptions{Host: opts.Host})
+	if err != nil {
+		return result, err
+	}
+	result.TorrentID = added.ID
+
+	// A downloaded torrent is kept, as its links may already be in the result
+	downloaded := false
+	defer func() {
+		if err != nil && opts.DeleteOnFailure && !downloaded {
+			// The context may be the reason of the failure, so it can't be used for cleaning up
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), resolveCleanupTimeout)
+			defer cancel()
+			_ = c.Torrents.DeleteContext(cleanupCtx, added.ID)
+		}
+	}()
+
+	info, err := c.Torrents.WaitForStatusWithOptions(ctx, added.ID, opts.Wait, StatusWaitingFiles, StatusDownloaded)
+	if err != nil {
+		return result, err
+	}
+
+	if info.Status == StatusWaitingFiles {
+		ids := selector(info.Files)
+		if len(ids) == 0 {
+			return result, fmt.Errorf("no files selected from torrent %s", added.ID)
+		}
+
+		if err := c.Torrents.SelectFilesFromTorrentContext(ctx, added.ID, ids); err != nil {
+			return result, err
+		}
+
+		if info, err = c.Torrents.WaitForStatusWithOptions(ctx, added.ID, opts.Wait, StatusDownloaded); err != nil {
+			return result, err
+		}
+	}
+
+	downloaded = true
+
+	// The links of a downloaded torrent follow the order of its selected files, unless they got
+	// packed into archives, which leaves fewer links than files
+	var selected []File
+	for _, f := range info.Files {
+		if f.Selected == 1 {
+			selected = append(selected, f)
+		}
+	}
+	result.Packed = len(selected) != len(info.Links)
+
+	for i, link := range info.Links {
+		resolved := ResolvedFile{Link: link}
+		if !result.Packed {
+			resolved.File = selected[i]
+		}
+		resolved.Info, resolved.Err = c.Unrestrict.UnrestrictContext(ctx, resolved.Link, opts.Unrestrict)
+		result.Files = append(result.Files, resolved)
+
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package rd_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewResolveTestClient(fn TestRoundTripFunc) *rd.RealDebrid {
+	c := &http.Client{
+		Transport: fn,
+	}
+	return rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		Header: map[string][]string{
+			"Content-Type": {"application/json"},
+		},
+	}
+}
+
+func TestRealDebrid_Resolve(t *testing.T) {
+	selected := false
+	client := NewResolveTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/rest/1.0/torrents/addMagnet":
+			assert.Equal(t, "magnet-url", req.FormValue("magnet"))
+			return jsonResponse(http.StatusCreated, `{ "id": "XCBYL4ZIYPU42", "uri": "" }`)
+		case "/rest/1.0/torrents/info/XCBYL4ZIYPU42":
+			if !selected {
+				return jsonResponse(http.StatusOK, `{ "id": "XCBYL4ZIYPU42", "status": "waiting_files_selection", "files": [
+{ "id": 1, "path": "/movie.mkv", "bytes": 1000, "selected": 0 },
+{ "id": 2, "path": "/sample.mkv", "bytes": 10, "selected": 0 },
+{ "id": 3, "path": "/movie.srt", "bytes": 1, "selected": 0 } ] }`)
+			}
+			return jsonResponse(http.StatusOK, `{ "id": "XCBYL4ZIYPU42", "status": "downloaded", "files": [
+{ "id": 1, "path": "/movie.mkv", "bytes": 1000, "selected": 1 },
+{ "id": 2, "path": "/sample.mkv", "bytes": 10, "selected": 0 },
+{ "id": 3, "path": "/movie.srt", "bytes": 1, "selected": 1 } ],
+"links": [ "https://real-debrid.com/d/MOVIE", "https://real-debrid.com/d/SUBTITLES" ] }`)
+		case "/rest/1.0/torrents/selectFiles/XCBYL4ZIYPU42":
+			assert.Equal(t, "1,3", req.FormValue("files"))
+			selected = true
+			return jsonResponse(http.StatusNoContent, ``)
+		case "/rest/1.0/unrestrict/link":
+			if req.FormValue("link") == "https://real-debrid.com/d/SUBTITLES" {
+				return jsonResponse(http.StatusServiceUnavailable, `{ "error": "file_unavailable", "error_code": 24 }`)
+			}
+			return jsonResponse(http.StatusOK, `{ "id": "MOVIE", "download": "https://30.rdeb.io/d/MOVIE/movie.mkv" }`)
+		}
+		t.Errorf("unexpected request to %s", req.URL)
+		return nil
+	})
+
+	result, err := client.ResolveWithOptions(context.Background(), "magnet-url", func(files []rd.File) []int {
+		return []int{1, 3}
+	}, rd.ResolveOptions{Wait: rd.WaitOptions{Interval: time.Millisecond}})
+	assert.NoError(t, err)
+	assert.Equal(t, "XCBYL4ZIYPU42", result.TorrentID)
+	assert.False(t, result.Packed)
+	assert.Len(t, result.Files, 2)
+
+	assert.Equal(t, "/movie.mkv", result.Files[0].File.Path)
+	assert.Equal(t, "https://real-debrid.com/d/MOVIE", result.Files[0].Link)
+	assert.Equal(t, "https://30.rdeb.io/d/MOVIE/movie.mkv", result.Files[0].Info.Download)
+	assert.NoError(t, result.Files[0].Err)
+
+	assert.Equal(t, "/movie.srt", result.Files[1].File.Path)
+	assert.Equal(t, "https://real-debrid.com/d/SUBTITLES", result.Files[1].Link)
+	assert.Error(t, result.Files[1].Err)
+}
+
+func TestRealDebrid_ResolvePackedFiles(t *testing.T) {
+	client := NewResolveTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/rest/1.0/torrents/addMagnet":
+			return jsonResponse(http.StatusCreated, `{ "id": "XCBYL4ZIYPU42", "uri": "" }`)
+		case "/rest/1.0/torrents/info/XCBYL4ZIYPU42":
+			return jsonResponse(http.StatusOK, `{ "id": "XCBYL4ZIYPU42", "status": "downloaded", "files": [
+{ "id": 1, "path": "/movie.mkv", "bytes": 1000, "selected": 1 },
+{ "id": 2, "path": "/movie.srt", "bytes": 1, "selected": 1 } ],
+"links": [ "https://real-debrid.com/d/ARCHIVE" ] }`)
+		case "/rest/1.0/unrestrict/link":
+			assert.Equal(t, "https://real-debrid.com/d/ARCHIVE", req.FormValue("link"))
+			return jsonResponse(http.StatusOK, `{ "id": "ARCHIVE", "download": "https://30.rdeb.io/d/ARCHIVE/movie.rar" }`)
+		case "/rest/1.0/torrents/delete/XCBYL4ZIYPU42":
+			t.Error("downloaded torrent should not be deleted")
+			return jsonResponse(http.StatusNoContent, ``)
+		}
+		t.Errorf("unexpected request to %s", req.URL)
+		return nil
+	})
+
+	result, err := client.ResolveWithOptions(context.Background(), "magnet-url", nil, rd.ResolveOptions{DeleteOnFailure: true})
+	assert.NoError(t, err)
+	assert.True(t, result.Packed)
+	assert.Len(t, result.Files, 1)
+	assert.Equal(t, rd.File{}, result.Files[0].File)
+	assert.Equal(t, "https://real-debrid.com/d/ARCHIVE", result.Files[0].Link)
+	assert.Equal(t, "https://30.rdeb.io/d/ARCHIVE/movie.rar", result.Files[0].Info.Download)
+}
+
+func TestRealDebrid_ResolveDeletesTorrentOnFailure(t *testing.T) {
+	deleted := false
+	client := NewResolveTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/rest/1.0/torrents/addMagnet":
+			return jsonResponse(http.StatusCreated, `{ "id": "XCBYL4ZIYPU42", "uri": "" }`)
+		case "/rest/1.0/torrents/info/XCBYL4ZIYPU42":
+			return jsonResponse(http.StatusOK, `{ "id": "XCBYL4ZIYPU42", "status": "magnet_error" }`)
+		case "/rest/1.0/torrents/delete/XCBYL4ZIYPU42":
+			assert.Equal(t, "DELETE", req.Method)
+			_, bounded := req.Context().Deadline()
+			assert.True(t, bounded)
+			deleted = true
+			return jsonResponse(http.StatusNoContent, ``)
+		}
+		t.Errorf("unexpected request to %s", req.URL)
+		return nil
+	})
+
+	result, err := client.ResolveWithOptions(context.Background(), "magnet-url", nil, rd.ResolveOptions{DeleteOnFailure: true})
+	assert.Equal(t, rd.TorrentStatusError{ID: "XCBYL4ZIYPU42", Status: rd.StatusMagnetError}, err)
+	assert.Equal(t, "XCBYL4ZIYPU42", result.TorrentID)
+	assert.True(t, deleted)
+}
+
+func TestRealDebrid_ResolveKeepsDownloadedTorrentWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewResolveTestClient(func(req *http.Request) *http.Response {
+		switch req.URL.Path {
+		case "/rest/1.0/torrents/addMagnet":
+			return jsonResponse(http.StatusCreated, `{ "id": "XCBYL4ZIYPU42", "uri": "" }`)
+		case "/rest/1.0/torrents/info/XCBYL4ZIYPU42":
+			return jsonResponse(http.StatusOK, `{ "id": "XCBYL4ZIYPU42", "status": "downloaded", "files": [
+{ "id": 1, "path": "/movie.mkv", "bytes": 1000, "selected": 1 },
+{ "id": 2, "path": "/movie.srt", "bytes": 1, "selected": 1 } ],
+"links": [ "https://real-debrid.com/d/MOVIE", "https://real-debrid.com/d/SUBTITLES" ] }`)
+		case "/rest/1.0/unrestrict/link":
+			cancel()
+			return jsonResponse(http.StatusOK, `{ "id": "MOVIE", "download": "https://30.rdeb.io/d/MOVIE/movie.mkv" }`)
+		case "/rest/1.0/torrents/delete/XCBYL4ZIYPU42":
+			t.Error("downloaded torrent should not be deleted")
+			return jsonResponse(http.StatusNoContent, ``)
+		}
+		t.Errorf("unexpected request to %s", req.URL)
+		return nil
+	})
+
+	result, err := client.ResolveWithOptions(ctx, "magnet-url", nil, rd.ResolveOptions{DeleteOnFailure: true})
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, result.Files, 1)
+	assert.Equal(t, "https://30.rdeb.io/d/MOVIE/movie.mkv", result.Files[0].Info.Download)
+}
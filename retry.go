@@ -0,0 +1,148 @@
+package rd
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides if a failed request should be sent again and how long to wait before doing so
+type RetryPolicy interface {
+	// Retry receives the number of the attempt that just failed, starting from 1, together with
+	// its request, response and error. It returns the delay before the next attempt and whether
+	// there should be one at all.
+	Retry(attempt int, r *http.Request, resp *http.Response, err error) (delay time.Duration, retry bool)
+}
+
+// ExponentialBackoff retries transient failures with an exponentially growing, jittered delay.
+// Only idempotent requests are retried on network errors and 5xx responses, while the
+// "Slow down" and "Service unavailable" error codes are retried for every request, as the
+// API rejected them without processing.
+type ExponentialBackoff struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt, doubled for every attempt after it
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts, it is ignored if zero
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is a sensible retry policy for the RealDebrid API
+var DefaultRetryPolicy = ExponentialBackoff{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+
+// WithRetryPolicy makes the client retry failed requests according to the given policy. Requests that
+// failed before being sent, like with a disabled token or a failed token refresh, are never retried.
+func WithRetryPolicy(policy RetryPolicy) func(*HTTPClient) {
+	return func(c *HTTPClient) {
+		c.retry = policy
+	}
+}
+
+func (b ExponentialBackoff) Retry(attempt int, r *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	if err == nil || attempt >= b.MaxAttempts || !isRetryable(r, resp, err) {
+		return 0, false
+	}
+
+	delay := b.BaseDelay << uint(attempt-1)
+	if b.MaxDelay > 0 && (delay > b.MaxDelay || delay <= 0) {
+		delay = b.MaxDelay
+	}
+	if delay > 0 {
+		// Full jitter on the upper half, so concurrent clients don't retry in lockstep
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+
+	if after := retryAfter(resp); after > delay {
+		delay = after
+	}
+
+	return delay, true
+}
+
+func isRetryable(r *http.Request, resp *http.Response, err error) bool {
+	if r.Context().Err() != nil {
+		return false
+	}
+
+	if isErrorCode(err, ErrSlowDown) || isErrorCode(err, ErrServiceUnavailable) {
+		return true
+	}
+
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !isIdempotent(r.Method) {
+		return false
+	}
+
+	return resp == nil || resp.StatusCode >= 500
+}
+
+// isIdempotent leaves out PUT, as the API uses it for uploads like adding a torrent file,
+// which would create a duplicate when sent again
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "DELETE":
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which can hold either seconds or an HTTP date
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
+
+// rewindBody resets the body of the request so it can be sent again
+func rewindBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+
+	if r.GetBody == nil {
+		return false
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return false
+	}
+	r.Body = body
+	return true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,186 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewRetryTestClient(fn TestRoundTripFunc) *rd.RealDebrid {
+	c := &http.Client{
+		Transport: fn,
+	}
+	return rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c, rd.WithRetryPolicy(rd.ExponentialBackoff{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+}
+
+func TestRetry_RetriesSlowDownWithRewoundBody(t *testing.T) {
+	calls := 0
+	client := NewRetryTestClient(func(req *http.Request) *http.Response {
+		calls++
+		assert.Equal(t, "magnet-url", req.FormValue("magnet"))
+		assert.Equal(t, []string{"Bearer VALID_TOKEN"}, req.Header["Authorization"])
+
+		if calls < 3 {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "slow_down", "error_code": 5 }`)),
+				Header: map[string][]string{
+					"Content-Type": {"application/json"},
+				},
+			}
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "id": "MNREAKNMGAG7C", "uri": "" }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	info, err := client.Torrents.AddMagnetLinkSimple("magnet-url")
+	assert.NoError(t, err)
+	assert.Equal(t, "MNREAKNMGAG7C", info.ID)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_StopsAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	client := NewRetryTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "service_unavailable", "error_code": 25 }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	_, err := client.Torrents.GetTorrent("XCBYL4ZIYPU42")
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_DoesNotRetryNonIdempotentServerErrors(t *testing.T) {
+	calls := 0
+	client := NewRetryTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "internal", "error_code": -1 }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	_, err := client.Unrestrict.SimpleUnrestrict("test-link-here")
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_DoesNotRetryUploadServerErrors(t *testing.T) {
+	calls := 0
+	client := NewRetryTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusBadGateway,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`bad gateway`)),
+			Header: map[string][]string{
+				"Content-Type": {"text/html"},
+			},
+			Request: req,
+		}
+	})
+
+	_, err := client.Torrents.AddTorrentFile(bytes.NewReader([]byte("torrent-file")), rd.AddTorrentOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_RetriesIdempotentServerErrors(t *testing.T) {
+	calls := 0
+	client := NewRetryTestClient(func(req *http.Request) *http.Response {
+		calls++
+		if calls == 1 {
+			return &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`bad gateway`)),
+				Header: map[string][]string{
+					"Content-Type": {"text/html"},
+				},
+				Request: req,
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	err := client.Downloads.Delete("XCBYL4ZIYPU42")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetry_DoesNotRetryAfterLogout(t *testing.T) {
+	calls := 0
+	c := &http.Client{
+		Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			calls++
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header: map[string][]string{
+					"Content-Type": {"application/json"},
+				},
+			}
+		}),
+	}
+	client := rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c, rd.WithRetryPolicy(rd.ExponentialBackoff{MaxAttempts: 3, BaseDelay: time.Second}))
+	assert.NoError(t, client.Logout())
+
+	start := time.Now()
+	_, err := client.Torrents.GetTorrent("XCBYL4ZIYPU42")
+	assert.Equal(t, rd.ErrTokenDisabled, err)
+	assert.True(t, time.Since(start) < 100*time.Millisecond, "retried for %s", time.Since(start))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_DoesNotRetryOnRefreshFailure(t *testing.T) {
+	var urls []string
+	c := &http.Client{
+		Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			urls = append(urls, req.URL.String())
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "service_unavailable", "error_code": 25 }`)),
+				Header: map[string][]string{
+					"Content-Type": {"application/json"},
+				},
+			}
+		}),
+	}
+	client := rd.NewRealDebrid(
+		rd.Token{AccessToken: "EXPIRED_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c, rd.AutoRefresh, rd.WithRetryPolicy(rd.ExponentialBackoff{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	_, err := client.Torrents.GetTorrent("XCBYL4ZIYPU42")
+	assert.Error(t, err)
+	assert.Equal(t, []string{
+		"https://api.real-debrid.com/oauth/v2/device/credentials?client_id=X245A4XAIBGVM&code=REFRESH_TOKEN",
+	}, urls)
+}
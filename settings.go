@@ -0,0 +1,133 @@
+package rd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// Endpoints
+const (
+	settingsPath               = "/settings"
+	settingsUpdatePath         = "/settings/update"
+	settingsConvertPointsPath  = "/settings/convertPoints"
+	settingsChangePasswordPath = "/settings/changePassword"
+	settingsAvatarFilePath     = "/settings/avatarFile"
+	settingsAvatarDeletePath   = "/settings/avatarDelete"
+)
+
+// Settings that can be updated, the allowed values of each are listed in Settings
+const (
+	SettingDownloadPort                 SettingName = "download_port"
+	SettingLocale                       SettingName = "locale"
+	SettingStreamingLanguagePreference  SettingName = "streaming_language_preference"
+	SettingStreamingQuality             SettingName = "streaming_quality"
+	SettingMobileStreamingQuality       SettingName = "mobile_streaming_quality"
+	SettingStreamingCastAudioPreference SettingName = "streaming_cast_audio_preference"
+)
+
+type (
+	SettingName string
+
+	// Settings holds the current settings of the account, together with their allowed values
+	Settings struct {
+		DownloadPorts                []string          `json:"download_ports"`
+		DownloadPort                 string            `json:"download_port"`
+		Locales                      map[string]string `json:"locales"`
+		Locale                       string            `json:"locale"`
+		StreamingQualities           []string          `json:"streaming_qualities"`
+		StreamingQuality             string            `json:"streaming_quality"`
+		MobileStreamingQuality       string            `json:"mobile_streaming_quality"`
+		StreamingLanguages           map[string]string `json:"streaming_languages"`
+		StreamingLanguagePreference  string            `json:"streaming_language_preference"`
+		StreamingCastAudio           []string          `json:"streaming_cast_audio"`
+		StreamingCastAudioPreference string            `json:"streaming_cast_audio_preference"`
+	}
+
+	SettingsService interface {
+		Get() (Settings, error)
+		GetContext(ctx context.Context) (Settings, error)
+		Update(name SettingName, value string) error
+		UpdateContext(ctx context.Context, name SettingName, value string) error
+		ConvertPoints() error
+		ConvertPointsContext(ctx context.Context) error
+		ChangePassword() error
+		ChangePasswordContext(ctx context.Context) error
+		UploadAvatar(r io.Reader) error
+		UploadAvatarContext(ctx context.Context, r io.Reader) error
+		DeleteAvatar() error
+		DeleteAvatarContext(ctx context.Context) error
+	}
+
+	SettingsClient struct {
+		HTTPDoer
+	}
+)
+
+func (c *SettingsClient) Get() (settings Settings, err error) {
+	return c.GetContext(context.Background())
+}
+
+func (c *SettingsClient) GetContext(ctx context.Context) (settings Settings, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, settingsPath))
+	if err != nil {
+		return settings, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&settings)
+	return settings, err
+}
+
+// Update changes the setting to the value, which must be one of the allowed values listed in Settings
+func (c *SettingsClient) Update(name SettingName, value string) error {
+	return c.UpdateContext(context.Background(), name, value)
+}
+
+func (c *SettingsClient) UpdateContext(ctx context.Context, name SettingName, value string) error {
+	_, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, settingsUpdatePath), map[string]string{
+		"setting_name":  string(name),
+		"setting_value": value,
+	})
+	return err
+}
+
+// ConvertPoints converts the fidelity points of the account into premium days
+func (c *SettingsClient) ConvertPoints() error {
+	return c.ConvertPointsContext(context.Background())
+}
+
+func (c *SettingsClient) ConvertPointsContext(ctx context.Context) error {
+	_, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, settingsConvertPointsPath), nil)
+	return err
+}
+
+// ChangePassword sends the verification email for changing the password of the account
+func (c *SettingsClient) ChangePassword() error {
+	return c.ChangePasswordContext(context.Background())
+}
+
+func (c *SettingsClient) ChangePasswordContext(ctx context.Context) error {
+	_, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, settingsChangePasswordPath), nil)
+	return err
+}
+
+// UploadAvatar replaces the avatar of the account with the uploaded image
+func (c *SettingsClient) UploadAvatar(r io.Reader) error {
+	return c.UploadAvatarContext(context.Background(), r)
+}
+
+func (c *SettingsClient) UploadAvatarContext(ctx context.Context, r io.Reader) error {
+	_, err := httpPut(ctx, c, apiURL(c.HTTPDoer, settingsAvatarFilePath), r)
+	return err
+}
+
+// DeleteAvatar resets the avatar of the account to the default one
+func (c *SettingsClient) DeleteAvatar() error {
+	return c.DeleteAvatarContext(context.Background())
+}
+
+func (c *SettingsClient) DeleteAvatarContext(ctx context.Context) error {
+	_, err := httpDelete(ctx, c, apiURL(c.HTTPDoer, settingsAvatarDeletePath))
+	return err
+}
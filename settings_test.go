@@ -0,0 +1,139 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewSettingsTestClient(fn TestRoundTripFunc) rd.SettingsService {
+	c := &http.Client{
+		Transport: fn,
+	}
+	return rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c).Settings
+}
+
+func TestClient_GetSettings(t *testing.T) {
+	client := NewSettingsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/settings", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{
+    "download_ports": ["normal", "secure"],
+    "download_port": "secure",
+    "locales": { "en": "English", "fr": "Français" },
+    "locale": "en",
+    "streaming_qualities": ["original", "1080p", "720p"],
+    "streaming_quality": "original",
+    "mobile_streaming_quality": "720p",
+    "streaming_languages": { "eng": "English" },
+    "streaming_language_preference": "eng",
+    "streaming_cast_audio": ["stereo", "original"],
+    "streaming_cast_audio_preference": "stereo"
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	settings, err := client.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, rd.Settings{
+		DownloadPorts:                []string{"normal", "secure"},
+		DownloadPort:                 "secure",
+		Locales:                      map[string]string{"en": "English", "fr": "Français"},
+		Locale:                       "en",
+		StreamingQualities:           []string{"original", "1080p", "720p"},
+		StreamingQuality:             "original",
+		MobileStreamingQuality:       "720p",
+		StreamingLanguages:           map[string]string{"eng": "English"},
+		StreamingLanguagePreference:  "eng",
+		StreamingCastAudio:           []string{"stereo", "original"},
+		StreamingCastAudioPreference: "stereo",
+	}, settings)
+}
+
+func TestClient_UpdateSetting(t *testing.T) {
+	client := NewSettingsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/settings/update", req.URL.String())
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "download_port", req.FormValue("setting_name"))
+		assert.Equal(t, "normal", req.FormValue("setting_value"))
+
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	err := client.Update(rd.SettingDownloadPort, "normal")
+	assert.NoError(t, err)
+}
+
+func TestClient_ConvertPointsAndChangePassword(t *testing.T) {
+	var paths []string
+	client := NewSettingsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "POST", req.Method)
+		paths = append(paths, req.URL.Path)
+
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	assert.NoError(t, client.ConvertPoints())
+	assert.NoError(t, client.ChangePassword())
+	assert.Equal(t, []string{"/rest/1.0/settings/convertPoints", "/rest/1.0/settings/changePassword"}, paths)
+}
+
+func TestClient_UploadAvatar(t *testing.T) {
+	client := NewSettingsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/settings/avatarFile", req.URL.String())
+		assert.Equal(t, "PUT", req.Method)
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "image-content", string(body))
+
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	err := client.UploadAvatar(bytes.NewBufferString("image-content"))
+	assert.NoError(t, err)
+}
+
+func TestClient_DeleteAvatar(t *testing.T) {
+	client := NewSettingsTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/settings/avatarDelete", req.URL.String())
+		assert.Equal(t, "DELETE", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	err := client.DeleteAvatar()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,148 @@
+package rd
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Endpoints
+const (
+	transcodePath  = "/streaming/transcode/%s"
+	mediaInfosPath = "/streaming/mediaInfos/%s"
+)
+
+// Possible media types
+const (
+	MediaMovie MediaType = "movie"
+	MediaShow  MediaType = "show"
+	MediaAudio MediaType = "audio"
+)
+
+type (
+	MediaType string
+
+	// TranscodeLinks holds the streaming links of every format, keyed by quality
+	TranscodeLinks struct {
+		Apple    map[string]string `json:"apple"`
+		Dash     map[string]string `json:"dash"`
+		LiveMP4  map[string]string `json:"liveMP4"`
+		H264WebM map[string]string `json:"h264WebM"`
+	}
+
+	VideoStream struct {
+		Stream     string `json:"stream"`
+		Lang       string `json:"lang"`
+		LangISO    string `json:"lang_iso"`
+		Codec      string `json:"codec"`
+		Colorspace string `json:"colorspace"`
+		Width      int    `json:"width"`
+		Height     int    `json:"height"`
+	}
+
+	AudioStream struct {
+		Stream   string  `json:"stream"`
+		Lang     string  `json:"lang"`
+		LangISO  string  `json:"lang_iso"`
+		Codec    string  `json:"codec"`
+		Sampling int     `json:"sampling"`
+		Channels float64 `json:"channels"`
+	}
+
+	SubtitleStream struct {
+		Stream  string `json:"stream"`
+		Lang    string `json:"lang"`
+		LangISO string `json:"lang_iso"`
+		Type    string `json:"type"`
+	}
+
+	// Streams of the media, keyed by their identifier
+	VideoStreams    map[string]VideoStream
+	AudioStreams    map[string]AudioStream
+	SubtitleStreams map[string]SubtitleStream
+
+	MediaDetails struct {
+		Video     VideoStreams    `json:"video"`
+		Audio     AudioStreams    `json:"audio"`
+		Subtitles SubtitleStreams `json:"subtitles"`
+	}
+
+	MediaInfo struct {
+		Filename     string       `json:"filename"`
+		Hoster       string       `json:"hoster"`
+		Link         string       `json:"link"`
+		Type         MediaType    `json:"type"`
+		Season       string       `json:"season"`
+		Episode      string       `json:"episode"`
+		Year         string       `json:"year"`
+		Duration     float64      `json:"duration"`
+		Bitrate      int64        `json:"bitrate"`
+		Size         int64        `json:"size"`
+		Details      MediaDetails `json:"details"`
+		PosterPath   string       `json:"poster_path"`
+		AudioImage   string       `json:"audio_image"`
+		BackdropPath string       `json:"backdrop_path"`
+	}
+
+	StreamingService interface {
+		Transcode(id string) (links TranscodeLinks, err error)
+		TranscodeContext(ctx context.Context, id string) (links TranscodeLinks, err error)
+		MediaInfos(id string) (info MediaInfo, err error)
+		MediaInfosContext(ctx context.Context, id string) (info MediaInfo, err error)
+	}
+
+	StreamingClient struct {
+		HTTPDoer
+	}
+)
+
+// Transcode returns the streaming links of the file with the given ID, as found in UnrestrictInfo or DownloadInfo
+func (c *StreamingClient) Transcode(id string) (links TranscodeLinks, err error) {
+	return c.TranscodeContext(context.Background(), id)
+}
+
+func (c *StreamingClient) TranscodeContext(ctx context.Context, id string) (links TranscodeLinks, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, transcodePath, id))
+	if err != nil {
+		return links, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&links)
+	return links, err
+}
+
+// MediaInfos returns the media information of the file with the given ID, as found in UnrestrictInfo or DownloadInfo
+func (c *StreamingClient) MediaInfos(id string) (info MediaInfo, err error) {
+	return c.MediaInfosContext(context.Background(), id)
+}
+
+func (c *StreamingClient) MediaInfosContext(ctx context.Context, id string) (info MediaInfo, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, mediaInfosPath, id))
+	if err != nil {
+		return info, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return info, err
+}
+
+func (s *VideoStreams) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*map[string]VideoStream)(s))
+}
+
+func (s *AudioStreams) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*map[string]AudioStream)(s))
+}
+
+func (s *SubtitleStreams) UnmarshalJSON(data []byte) error {
+	return unmarshalObject(data, (*map[string]SubtitleStream)(s))
+}
+
+// unmarshalObject decodes a JSON object, which the API sends as an empty array when it has no keys
+func unmarshalObject(data []byte, v interface{}) error {
+	if len(data) > 0 && data[0] == '[' {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,109 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewStreamingTestClient(fn TestRoundTripFunc) rd.StreamingService {
+	c := &http.Client{
+		Transport: fn,
+	}
+	return rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c).Streaming
+}
+
+func TestClient_Transcode(t *testing.T) {
+	client := NewStreamingTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/streaming/transcode/AIO2UCGAIAQMD", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{
+    "apple": { "full": "https://example.com/full.m3u8" },
+    "dash": { "full": "https://example.com/full.mpd" },
+    "liveMP4": { "1080p": "https://example.com/1080p.mp4", "720p": "https://example.com/720p.mp4" },
+    "h264WebM": { "1080p": "https://example.com/1080p.webm" }
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	links, err := client.Transcode("AIO2UCGAIAQMD")
+	assert.NoError(t, err)
+	assert.Equal(t, rd.TranscodeLinks{
+		Apple:    map[string]string{"full": "https://example.com/full.m3u8"},
+		Dash:     map[string]string{"full": "https://example.com/full.mpd"},
+		LiveMP4:  map[string]string{"1080p": "https://example.com/1080p.mp4", "720p": "https://example.com/720p.mp4"},
+		H264WebM: map[string]string{"1080p": "https://example.com/1080p.webm"},
+	}, links)
+}
+
+func TestClient_MediaInfos(t *testing.T) {
+	client := NewStreamingTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/streaming/mediaInfos/AIO2UCGAIAQMD", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{
+    "filename": "helloworld.mkv",
+    "hoster": "real-debrid.com",
+    "link": "https://real-debrid.com/d/HIMWA4NP4ZLGY",
+    "type": "movie",
+    "season": null,
+    "episode": null,
+    "year": "2018",
+    "duration": 5423.5,
+    "bitrate": 2008123,
+    "size": 1361435465,
+    "details": {
+        "video": { "und1": { "stream": "0:0", "lang": "Unknown", "lang_iso": "und", "codec": "h264", "colorspace": "yuv420p", "width": 1920, "height": 1080 } },
+        "audio": { "eng1": { "stream": "0:1", "lang": "English", "lang_iso": "eng", "codec": "aac", "sampling": 48000, "channels": 5.1 } },
+        "subtitles": []
+    },
+    "poster_path": "https://example.com/poster.jpg",
+    "audio_image": "",
+    "backdrop_path": "https://example.com/backdrop.jpg"
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	info, err := client.MediaInfos("AIO2UCGAIAQMD")
+	assert.NoError(t, err)
+	assert.Equal(t, rd.MediaInfo{
+		Filename: "helloworld.mkv",
+		Hoster:   "real-debrid.com",
+		Link:     "https://real-debrid.com/d/HIMWA4NP4ZLGY",
+		Type:     rd.MediaMovie,
+		Year:     "2018",
+		Duration: 5423.5,
+		Bitrate:  2008123,
+		Size:     1361435465,
+		Details: rd.MediaDetails{
+			Video: rd.VideoStreams{
+				"und1": {Stream: "0:0", Lang: "Unknown", LangISO: "und", Codec: "h264", Colorspace: "yuv420p", Width: 1920, Height: 1080},
+			},
+			Audio: rd.AudioStreams{
+				"eng1": {Stream: "0:1", Lang: "English", LangISO: "eng", Codec: "aac", Sampling: 48000, Channels: 5.1},
+			},
+		},
+		PosterPath:   "https://example.com/poster.jpg",
+		BackdropPath: "https://example.com/backdrop.jpg",
+	}, info)
+}
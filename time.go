@@ -0,0 +1,112 @@
+package rd
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoints
+const (
+	timePath    = "/time"
+	timeISOPath = "/time/iso"
+)
+
+const timeISOFormat = "2006-01-02T15:04:05-0700"
+
+type (
+	TimeService interface {
+		Get() (string, error)
+		GetContext(ctx context.Context) (string, error)
+		ISO() (time.Time, error)
+		ISOContext(ctx context.Context) (time.Time, error)
+	}
+
+	TimeClient struct {
+		HTTPDoer
+	}
+
+	// Clock corrects the local time by its offset to the server time. A nil Clock uses the local time.
+	Clock struct {
+		mu       sync.RWMutex
+		offset   time.Duration
+		measured bool
+	}
+)
+
+// Get returns the server time as formatted by the API, in the timezone of the server
+func (c *TimeClient) Get() (string, error) {
+	return c.GetContext(context.Background())
+}
+
+func (c *TimeClient) GetContext(ctx context.Context) (string, error) {
+	return c.get(ctx, timePath)
+}
+
+// ISO returns the server time
+func (c *TimeClient) ISO() (time.Time, error) {
+	return c.ISOContext(context.Background())
+}
+
+func (c *TimeClient) ISOContext(ctx context.Context) (time.Time, error) {
+	value, err := c.get(ctx, timeISOPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if t, err := time.Parse(timeISOFormat, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// get reads the time from the response body, which can either be plain text or a JSON string
+func (c *TimeClient) get(ctx context.Context, path string) (string, error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, path))
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(string(body)), `"`), nil
+}
+
+// Now returns the current time corrected by the offset
+func (c *Clock) Now() time.Time {
+	return time.Now().Add(c.Offset())
+}
+
+// Offset returns the difference between the server time and the local time
+func (c *Clock) Offset() time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offset
+}
+
+// synced returns the offset and whether it was measured at all
+func (c *Clock) synced() (time.Duration, bool) {
+	if c == nil {
+		return 0, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offset, c.measured
+}
+
+func (c *Clock) setOffset(offset time.Duration) {
+	c.mu.Lock()
+	c.offset = offset
+	c.measured = true
+	c.mu.Unlock()
+}
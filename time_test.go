@@ -0,0 +1,122 @@
+package rd_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTimeTestClient(token rd.Token, fn TestRoundTripFunc) *rd.RealDebrid {
+	c := &http.Client{
+		Transport: fn,
+	}
+	return rd.NewRealDebrid(token, c)
+}
+
+func TestClient_GetTime(t *testing.T) {
+	client := NewTimeTestClient(rd.Token{}, func(req *http.Request) *http.Response {
+		assert.Equal(t, "GET", req.Method)
+
+		body := "2019-01-20 11:31:01"
+		if req.URL.Path == "/rest/1.0/time/iso" {
+			body = "2019-01-20T11:31:01+0100"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header: map[string][]string{
+				"Content-Type": {"text/html"},
+			},
+		}
+	})
+
+	local, err := client.Time.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-01-20 11:31:01", local)
+
+	iso, err := client.Time.ISO()
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2019, 1, 20, 10, 31, 1, 0, time.UTC).Equal(iso))
+}
+
+func NewSyncClockTestClient(t *testing.T, token rd.Token, serverTime time.Time) *rd.RealDebrid {
+	return NewTimeTestClient(token, func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/time/iso", req.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(serverTime.Format("2006-01-02T15:04:05-0700"))),
+			Header: map[string][]string{
+				"Content-Type": {"text/html"},
+			},
+		}
+	})
+}
+
+func TestRealDebrid_SyncClockKeepsFreshTokenValid(t *testing.T) {
+	// The token was obtained while the local clock was already two hours behind the server
+	serverTime := time.Now().Add(2 * time.Hour)
+	offset := 2 * time.Hour
+	client := NewSyncClockTestClient(
+		t,
+		rd.Token{ExpiresIn: 3600, AccessToken: "VALID_TOKEN", ObtainedAt: time.Now(), ClockOffset: &offset},
+		serverTime,
+	)
+
+	assert.True(t, client.IsTokenValid())
+	assert.NoError(t, client.SyncClock(context.Background()))
+	assert.True(t, client.IsTokenValid())
+	assert.WithinDuration(t, serverTime, client.Now(), 2*time.Second)
+}
+
+func TestRealDebrid_SyncClockCorrectsTokenExpiry(t *testing.T) {
+	// The token was obtained while the local clock was right, but it fell two hours behind since then
+	serverTime := time.Now().Add(2 * time.Hour)
+	offset := time.Duration(0)
+	client := NewSyncClockTestClient(
+		t,
+		rd.Token{ExpiresIn: 3600, AccessToken: "VALID_TOKEN", ObtainedAt: time.Now(), ClockOffset: &offset},
+		serverTime,
+	)
+
+	assert.True(t, client.IsTokenValid())
+	assert.NoError(t, client.SyncClock(context.Background()))
+	assert.False(t, client.IsTokenValid())
+}
+
+func TestRealDebrid_SyncClockPinsUnknownOffset(t *testing.T) {
+	var saved []rd.Token
+	serverTime := time.Now().Add(2 * time.Hour)
+	client := rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, AccessToken: "VALID_TOKEN", ObtainedAt: time.Now()},
+		&http.Client{Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(serverTime.Format("2006-01-02T15:04:05-0700"))),
+				Header: map[string][]string{
+					"Content-Type": {"text/html"},
+				},
+			}
+		})},
+		rd.OnTokenRefresh(func(token rd.Token) error {
+			saved = append(saved, token)
+			return nil
+		}))
+
+	// Without a known offset the token is checked against the local time
+	assert.True(t, client.IsTokenValid())
+	assert.NoError(t, client.SyncClock(context.Background()))
+
+	// The local clock is assumed to have been right when the token was obtained, so the drift since is corrected
+	assert.False(t, client.IsTokenValid())
+	if assert.NotNil(t, client.Token().ClockOffset) {
+		assert.Equal(t, time.Duration(0), *client.Token().ClockOffset)
+	}
+	assert.Len(t, saved, 1)
+}
@@ -1,11 +1,13 @@
 package rd
 
 import (
+	"context"
 	"time"
 )
 
 type TokenRefresher interface {
 	RefreshAccessToken(token Token) (t Token, err error)
+	RefreshAccessTokenContext(ctx context.Context, token Token) (t Token, err error)
 }
 
 type Token struct {
@@ -14,11 +16,35 @@ type Token struct {
 	RefreshToken string `json:"refresh_token"`
 	TokenType    string `json:"token_type"`
 	ObtainedAt   time.Time
+
+	// ClockOffset is the offset between the server time and the local time when the token was obtained,
+	// as measured by Clock. It is nil when the offset is unknown.
+	ClockOffset *time.Duration `json:"clock_offset,omitempty"`
+
+	// ClientID and ClientSecret are the credentials the token was obtained with, used for refreshing it
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
 }
 
 // IsValid checks if the current token is not expired and valid
 func (t *Token) IsValid() bool {
+	return t.IsValidAt(time.Now())
+}
+
+// IsValidAt checks if the token is not expired and valid at the given local time
+func (t *Token) IsValidAt(now time.Time) bool {
 	// We expire the token 10 seconds before, so we don't send a request and risk to have it failing during transport
 	tokenExpiry := t.ObtainedAt.Add(time.Second * time.Duration(t.ExpiresIn-10))
-	return time.Now().Before(tokenExpiry)
+	return now.Before(tokenExpiry)
+}
+
+// IsValidWith checks if the token is not expired and valid according to the clock. When both the token
+// and the clock know their offset, the token is checked against the server time, so drift of the local
+// clock since the token was obtained is corrected. Otherwise it is checked against the local time.
+func (t *Token) IsValidWith(clock *Clock) bool {
+	offset, synced := clock.synced()
+	if t.ClockOffset == nil || !synced {
+		return t.IsValid()
+	}
+	return t.IsValidAt(time.Now().Add(offset - *t.ClockOffset))
 }
@@ -0,0 +1,79 @@
+package rd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// TokenStore persists tokens, so they survive restarts of the application
+type TokenStore interface {
+	Load() (Token, error)
+	Save(token Token) error
+}
+
+// FileTokenStore stores the token as JSON in a file
+type FileTokenStore struct {
+	Path string
+}
+
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{Path: path}
+}
+
+// Load reads the token from the file
+func (s *FileTokenStore) Load() (t Token, err error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		return t, err
+	}
+
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+
+// Save atomically replaces the file with the given token, readable only by the current user.
+// Saving an empty token removes the file.
+func (s *FileTokenStore) Save(token Token) error {
+	if token == (Token{}) {
+		if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.Path)
+}
+
+// OnTokenRefresh calls the given function whenever the client swaps its token, or with an empty
+// token after logging out. An error returned by the function fails the request that triggered the refresh.
+func OnTokenRefresh(fn func(token Token) error) func(*HTTPClient) {
+	return func(c *HTTPClient) {
+		c.onTokenRefresh = append(c.onTokenRefresh, fn)
+	}
+}
+
+// WithTokenStore saves every token the client obtains into the store
+func WithTokenStore(store TokenStore) func(*HTTPClient) {
+	return OnTokenRefresh(store.Save)
+}
@@ -0,0 +1,83 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileTokenStore_SavesAndLoadsToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := rd.NewFileTokenStore(filepath.Join(dir, "token.json"))
+	token := rd.Token{
+		AccessToken:  "ACCESS",
+		RefreshToken: "REFRESH",
+		ExpiresIn:    3600,
+		TokenType:    "Bearer",
+		ObtainedAt:   time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	assert.NoError(t, store.Save(token))
+	loaded, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, token, loaded)
+}
+
+func TestTokenStore_IsCalledAfterRefresh(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &http.Client{
+		Transport: TestRoundTripFunc(func(req *http.Request) *http.Response {
+			switch req.URL.Path {
+			case "/oauth/v2/device/credentials":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"client_id":"0N2RHHK5OKNIX","client_secret":"135d1b6dc60dddbcaa2e5dc1772c85d56c5479ba"}`)),
+					Header:     map[string][]string{"Content-Type": {"application/json"}},
+				}
+			case "/oauth/v2/token":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"access_token": "NEW_TOKEN", "expires_in": 3600, "refresh_token": "NEW_REFRESH_TOKEN", "token_type": "Bearer" }`)),
+					Header:     map[string][]string{"Content-Type": {"application/json"}},
+				}
+			}
+			assert.Equal(t, "Bearer NEW_TOKEN", req.Header.Get("Authorization"))
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header:     map[string][]string{"Content-Type": {"application/json"}},
+			}
+		}),
+	}
+
+	store := rd.NewFileTokenStore(filepath.Join(dir, "token.json"))
+	var refreshed rd.Token
+	client := rd.NewRealDebrid(
+		rd.Token{AccessToken: "EXPIRED_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c, rd.AutoRefresh, rd.WithTokenStore(store), rd.OnTokenRefresh(func(token rd.Token) error {
+			refreshed = token
+			return nil
+		}))
+
+	assert.NoError(t, client.Torrents.Delete("XCBYL4ZIYPU42"))
+	assert.Equal(t, "NEW_TOKEN", client.Token().AccessToken)
+	assert.Equal(t, "NEW_TOKEN", refreshed.AccessToken)
+
+	stored, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "NEW_TOKEN", stored.AccessToken)
+	assert.Equal(t, "NEW_REFRESH_TOKEN", stored.RefreshToken)
+}
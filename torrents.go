@@ -1,8 +1,11 @@
 package rd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -10,13 +13,26 @@ import (
 
 // Endpoints
 const (
-	magnetAddUrl          = apiBaseUrl + "/torrents/addMagnet"
-	torrentInfoUrl        = apiBaseUrl + "/torrents/info/%s"
-	torrentsUrl           = apiBaseUrl + "/torrents"
-	torrentDeleteUrl      = apiBaseUrl + "/torrents/delete/%s"
-	torrentSelectFilesUrl = apiBaseUrl + "/torrents/selectFiles/%s"
+	magnetAddPath           = "/torrents/addMagnet"
+	torrentAddPath          = "/torrents/addTorrent"
+	torrentInfoPath         = "/torrents/info/%s"
+	torrentsPath            = "/torrents"
+	torrentDeletePath       = "/torrents/delete/%s"
+	torrentSelectFilesPath  = "/torrents/selectFiles/%s"
+	instantAvailabilityPath = "/torrents/instantAvailability"
+	activeCountPath         = "/torrents/activeCount"
+	availableHostsPath      = "/torrents/availableHosts"
 )
 
+// Defaults for waiting on torrent states
+const (
+	defaultWaitInterval = 2 * time.Second
+	defaultWaitBackoff  = 1
+)
+
+// Keeps the instant availability URLs well below the length limits of servers and proxies
+const maxInstantAvailabilityURLLength = 2000
+
 // Possible torrent states
 const (
 	StatusMagnetError      Status = "magnet_error"
@@ -35,10 +51,30 @@ const (
 type (
 	TorrentService interface {
 		AddMagnetLinkSimple(magnet string) (info TorrentUrlInfo, err error)
+		AddMagnetLinkSimpleContext(ctx context.Context, magnet string) (info TorrentUrlInfo, err error)
+		AddMagnetLink(magnet string, opts AddTorrentOptions) (info TorrentUrlInfo, err error)
+		AddMagnetLinkContext(ctx context.Context, magnet string, opts AddTorrentOptions) (info TorrentUrlInfo, err error)
+		AddTorrentFile(r io.Reader, opts AddTorrentOptions) (info TorrentUrlInfo, err error)
+		AddTorrentFileContext(ctx context.Context, r io.Reader, opts AddTorrentOptions) (info TorrentUrlInfo, err error)
 		SelectFilesFromTorrent(id string, fileIds []int) error
+		SelectFilesFromTorrentContext(ctx context.Context, id string, fileIds []int) error
 		GetTorrent(id string) (info TorrentInfo, err error)
+		GetTorrentContext(ctx context.Context, id string) (info TorrentInfo, err error)
 		GetTorrents() (infos []TorrentInfo, err error)
+		GetTorrentsContext(ctx context.Context) (infos []TorrentInfo, err error)
+		GetTorrentsPage(opts ListOptions) (page TorrentsPage, err error)
+		GetTorrentsPageContext(ctx context.Context, opts ListOptions) (page TorrentsPage, err error)
+		AllTorrents(ctx context.Context, opts ListOptions) *TorrentIterator
 		Delete(id string) error
+		DeleteContext(ctx context.Context, id string) error
+		InstantAvailability(hashes ...string) (map[string]HashAvailability, error)
+		InstantAvailabilityContext(ctx context.Context, hashes ...string) (map[string]HashAvailability, error)
+		ActiveCount() (count ActiveCount, err error)
+		ActiveCountContext(ctx context.Context) (count ActiveCount, err error)
+		AvailableHosts() (hosts []TorrentHost, err error)
+		AvailableHostsContext(ctx context.Context) (hosts []TorrentHost, err error)
+		WaitForStatus(ctx context.Context, id string, targets ...Status) (info TorrentInfo, err error)
+		WaitForStatusWithOptions(ctx context.Context, id string, opts WaitOptions, targets ...Status) (info TorrentInfo, err error)
 	}
 
 	TorrentClient struct {
@@ -47,6 +83,11 @@ type (
 
 	Status string
 
+	AddTorrentOptions struct {
+		// Host is the hoster domain the torrent is downloaded to, the API picks one if empty
+		Host string
+	}
+
 	TorrentUrlInfo struct {
 		ID  string `json:"id"`
 		URI string `json:"uri"`
@@ -59,6 +100,58 @@ type (
 		Selected int    `json:"selected"`
 	}
 
+	// WaitOptions configures the polling of WaitForStatusWithOptions
+	WaitOptions struct {
+		// Interval is the delay between two polls, two seconds if zero
+		Interval time.Duration
+		// Backoff multiplies the interval after every poll, it stays constant if lower than or equal to one
+		Backoff float64
+		// MaxInterval caps the interval growing with Backoff, it is ignored if zero
+		MaxInterval time.Duration
+		// OnUpdate is called whenever the status, progress, speed or seeders of the torrent change
+		OnUpdate func(info TorrentInfo)
+	}
+
+	// TorrentStatusError is returned when a torrent ends up in a failed state while waiting for it
+	TorrentStatusError struct {
+		ID     string
+		Status Status
+	}
+
+	TorrentsPage struct {
+		PaginatedResponse
+		Items []TorrentInfo
+	}
+
+	// TorrentIterator walks lazily through the torrents of the account, fetching one page at a time
+	TorrentIterator struct {
+		pager
+		client  *TorrentClient
+		items   []TorrentInfo
+		current TorrentInfo
+	}
+
+	ActiveCount struct {
+		Count int `json:"nb"`
+		Limit int `json:"limit"`
+	}
+
+	TorrentHost struct {
+		Host        string `json:"host"`
+		MaxFileSize int64  `json:"max_file_size"`
+	}
+
+	CachedFile struct {
+		Filename string `json:"filename"`
+		Filesize int64  `json:"filesize"`
+	}
+
+	// CachedVariant is a selection of files of a torrent that is cached together, keyed by file ID
+	CachedVariant map[int]CachedFile
+
+	// HashAvailability lists the cached variants of a torrent, keyed by host
+	HashAvailability map[string][]CachedVariant
+
 	TorrentInfo struct {
 		ID               string    `json:"id"`
 		Filename         string    `json:"filename"`
@@ -80,7 +173,47 @@ type (
 )
 
 func (c *TorrentClient) AddMagnetLinkSimple(magnet string) (info TorrentUrlInfo, err error) {
-	resp, err := httpPostForm(c, magnetAddUrl, map[string]string{"magnet": magnet})
+	return c.AddMagnetLinkSimpleContext(context.Background(), magnet)
+}
+
+func (c *TorrentClient) AddMagnetLinkSimpleContext(ctx context.Context, magnet string) (info TorrentUrlInfo, err error) {
+	return c.AddMagnetLinkContext(ctx, magnet, AddTorrentOptions{})
+}
+
+// AddMagnetLink adds the magnet link using the given options
+func (c *TorrentClient) AddMagnetLink(magnet string, opts AddTorrentOptions) (info TorrentUrlInfo, err error) {
+	return c.AddMagnetLinkContext(context.Background(), magnet, opts)
+}
+
+func (c *TorrentClient) AddMagnetLinkContext(ctx context.Context, magnet string, opts AddTorrentOptions) (info TorrentUrlInfo, err error) {
+	values := map[string]string{"magnet": magnet}
+	if opts.Host != "" {
+		values["host"] = opts.Host
+	}
+
+	resp, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, magnetAddPath), values)
+	if err != nil {
+		return info, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return info, err
+}
+
+// AddTorrentFile uploads the content of a .torrent file. A too big or invalid torrent fails with
+// ErrTorrentTooBig or ErrTorrentFileInvalid.
+func (c *TorrentClient) AddTorrentFile(r io.Reader, opts AddTorrentOptions) (info TorrentUrlInfo, err error) {
+	return c.AddTorrentFileContext(context.Background(), r, opts)
+}
+
+func (c *TorrentClient) AddTorrentFileContext(ctx context.Context, r io.Reader, opts AddTorrentOptions) (info TorrentUrlInfo, err error) {
+	params := map[string]string{}
+	if opts.Host != "" {
+		params["host"] = opts.Host
+	}
+
+	resp, err := httpPut(ctx, c, apiURL(c.HTTPDoer, torrentAddPath), r, params)
 	if err != nil {
 		return info, err
 	}
@@ -91,12 +224,20 @@ func (c *TorrentClient) AddMagnetLinkSimple(magnet string) (info TorrentUrlInfo,
 }
 
 func (c *TorrentClient) SelectFilesFromTorrent(id string, fileIds []int) error {
-	_, err := httpPostForm(c, fmt.Sprintf(torrentSelectFilesUrl, id), map[string]string{"files": joinInts(fileIds)})
+	return c.SelectFilesFromTorrentContext(context.Background(), id, fileIds)
+}
+
+func (c *TorrentClient) SelectFilesFromTorrentContext(ctx context.Context, id string, fileIds []int) error {
+	_, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, torrentSelectFilesPath, id), map[string]string{"files": joinInts(fileIds)})
 	return err
 }
 
 func (c *TorrentClient) GetTorrent(id string) (info TorrentInfo, err error) {
-	resp, err := httpGet(c, fmt.Sprintf(torrentInfoUrl, id))
+	return c.GetTorrentContext(context.Background(), id)
+}
+
+func (c *TorrentClient) GetTorrentContext(ctx context.Context, id string) (info TorrentInfo, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, torrentInfoPath, id))
 	if err != nil {
 		return info, err
 	}
@@ -107,12 +248,57 @@ func (c *TorrentClient) GetTorrent(id string) (info TorrentInfo, err error) {
 }
 
 func (c *TorrentClient) Delete(id string) error {
-	_, err := httpDelete(c, fmt.Sprintf(torrentDeleteUrl, id))
+	return c.DeleteContext(context.Background(), id)
+}
+
+func (c *TorrentClient) DeleteContext(ctx context.Context, id string) error {
+	_, err := httpDelete(ctx, c, apiURL(c.HTTPDoer, torrentDeletePath, id))
 	return err
 }
 
 func (c *TorrentClient) GetTorrents() (infos []TorrentInfo, err error) {
-	resp, err := httpGet(c, torrentsUrl)
+	return c.GetTorrentsContext(context.Background())
+}
+
+// GetTorrentsPage returns the page of torrents selected by the options, with the total count of torrents
+func (c *TorrentClient) GetTorrentsPage(opts ListOptions) (page TorrentsPage, err error) {
+	return c.GetTorrentsPageContext(context.Background(), opts)
+}
+
+func (c *TorrentClient) GetTorrentsPageContext(ctx context.Context, opts ListOptions) (page TorrentsPage, err error) {
+	page.PaginatedResponse, err = getPage(ctx, c.HTTPDoer, torrentsPath, opts, &page.Items)
+	return page, err
+}
+
+// AllTorrents iterates over all the torrents matching the filter of the options, starting at their offset
+func (c *TorrentClient) AllTorrents(ctx context.Context, opts ListOptions) *TorrentIterator {
+	return &TorrentIterator{pager: newPager(ctx, opts), client: c}
+}
+
+// Next advances to the next torrent, fetching the next page when needed. It returns false
+// when there are no more torrents or an error occurred, which is then returned by Err.
+func (it *TorrentIterator) Next() bool {
+	for len(it.items) == 0 {
+		if !it.more() {
+			return false
+		}
+
+		page, err := it.client.GetTorrentsPageContext(it.ctx, it.opts)
+		it.items = page.Items
+		it.advance(page.PaginatedResponse, len(page.Items), err)
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Torrent returns the current torrent
+func (it *TorrentIterator) Torrent() TorrentInfo {
+	return it.current
+}
+
+func (c *TorrentClient) GetTorrentsContext(ctx context.Context) (infos []TorrentInfo, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, torrentsPath))
 	if err != nil {
 		return infos, err
 	}
@@ -122,6 +308,168 @@ func (c *TorrentClient) GetTorrents() (infos []TorrentInfo, err error) {
 	return infos, err
 }
 
+// InstantAvailability checks which of the torrent hashes are already cached, so they are available
+// instantly once added. The hashes are split into as many requests as needed to keep URLs short.
+func (c *TorrentClient) InstantAvailability(hashes ...string) (map[string]HashAvailability, error) {
+	return c.InstantAvailabilityContext(context.Background(), hashes...)
+}
+
+func (c *TorrentClient) InstantAvailabilityContext(ctx context.Context, hashes ...string) (map[string]HashAvailability, error) {
+	availabilities := make(map[string]HashAvailability, len(hashes))
+	base := apiURL(c.HTTPDoer, instantAvailabilityPath)
+
+	for len(hashes) > 0 {
+		u := base
+		n := 0
+		for n < len(hashes) && (n == 0 || len(u)+len(hashes[n])+1 <= maxInstantAvailabilityURLLength) {
+			u += "/" + hashes[n]
+			n++
+		}
+		hashes = hashes[n:]
+
+		if err := c.instantAvailability(ctx, u, availabilities); err != nil {
+			return nil, err
+		}
+	}
+
+	return availabilities, nil
+}
+
+func (c *TorrentClient) instantAvailability(ctx context.Context, u string, availabilities map[string]HashAvailability) error {
+	resp, err := httpGet(ctx, c, u)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return err
+	}
+
+	for hash, data := range raw {
+		// Hashes that are not cached come back as an empty array instead of an object
+		availability := HashAvailability{}
+		if err := unmarshalObject(data, &availability); err != nil {
+			return err
+		}
+		availabilities[hash] = availability
+	}
+
+	return nil
+}
+
+// IsAvailable checks if at least one variant of the torrent is cached
+func (a HashAvailability) IsAvailable() bool {
+	for _, variants := range a {
+		if len(variants) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveCount returns the number of currently active torrents and the limit of the account
+func (c *TorrentClient) ActiveCount() (count ActiveCount, err error) {
+	return c.ActiveCountContext(context.Background())
+}
+
+func (c *TorrentClient) ActiveCountContext(ctx context.Context) (count ActiveCount, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, activeCountPath))
+	if err != nil {
+		return count, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&count)
+	return count, err
+}
+
+// AvailableHosts returns the hosts torrents can be downloaded to
+func (c *TorrentClient) AvailableHosts() (hosts []TorrentHost, err error) {
+	return c.AvailableHostsContext(context.Background())
+}
+
+func (c *TorrentClient) AvailableHostsContext(ctx context.Context) (hosts []TorrentHost, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, availableHostsPath))
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&hosts)
+	return hosts, err
+}
+
+// IsFailed checks if the torrent can't progress anymore because of an error
+func (s Status) IsFailed() bool {
+	switch s {
+	case StatusMagnetError, StatusError, StatusVirus, StatusDead:
+		return true
+	}
+	return false
+}
+
+func (e TorrentStatusError) Error() string {
+	return fmt.Sprintf("torrent %s failed with status %s", e.ID, e.Status)
+}
+
+// WaitForStatus polls the torrent until it reaches one of the target states, using the default options
+func (c *TorrentClient) WaitForStatus(ctx context.Context, id string, targets ...Status) (info TorrentInfo, err error) {
+	return c.WaitForStatusWithOptions(ctx, id, WaitOptions{}, targets...)
+}
+
+// WaitForStatusWithOptions polls the torrent until it reaches one of the target states and returns
+// its last info. It fails with a TorrentStatusError as soon as the torrent reaches a failed state
+// that is not a target, or with the error of the context when it is done.
+func (c *TorrentClient) WaitForStatusWithOptions(ctx context.Context, id string, opts WaitOptions, targets ...Status) (info TorrentInfo, err error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+	// A backoff below one would shrink the interval until the torrent gets polled without any delay
+	backoff := opts.Backoff
+	if backoff < 1 {
+		backoff = defaultWaitBackoff
+	}
+
+	var last TorrentInfo
+	for poll := 0; ; poll++ {
+		info, err = c.GetTorrentContext(ctx, id)
+		if err != nil {
+			return info, err
+		}
+
+		if opts.OnUpdate != nil && (poll == 0 || info.Status != last.Status || info.Progress != last.Progress ||
+			info.Speed != last.Speed || info.Seeders != last.Seeders) {
+			opts.OnUpdate(info)
+		}
+		last = info
+
+		for _, target := range targets {
+			if info.Status == target {
+				return info, nil
+			}
+		}
+		if info.Status.IsFailed() {
+			return info, TorrentStatusError{ID: id, Status: info.Status}
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return info, err
+		}
+
+		if next := float64(interval) * backoff; next < math.MaxInt64 {
+			interval = time.Duration(next)
+		} else {
+			interval = math.MaxInt64
+		}
+		if opts.MaxInterval > 0 && interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
 func joinInts(slice []int) string {
 	b := make([]string, len(slice))
 	for i, v := range slice {
@@ -2,8 +2,11 @@ package rd_test
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -174,3 +177,371 @@ func TestClient_DeleteTorrent(t *testing.T) {
 	err := client.Delete("XCBYL4ZIYPU42")
 	assert.NoError(t, err)
 }
+
+func TestClient_AddTorrentFile(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents/addTorrent?host=real-debrid.com", req.URL.String())
+		assert.Equal(t, "PUT", req.Method)
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "torrent-content", string(body))
+
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body: ioutil.NopCloser(bytes.NewBufferString(
+				`{ "id": "MNREAKNMGAG7C", "uri": "https://api.real-debrid.com/rest/1.0/torrents/info/MNREAKNMGAG7C" }`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	urlInfo, err := client.AddTorrentFile(bytes.NewBufferString("torrent-content"), rd.AddTorrentOptions{Host: "real-debrid.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, rd.TorrentUrlInfo{ID: "MNREAKNMGAG7C", URI: "https://api.real-debrid.com/rest/1.0/torrents/info/MNREAKNMGAG7C"}, urlInfo)
+}
+
+func TestClient_AddInvalidTorrentFile(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents/addTorrent", req.URL.String())
+
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "error": "torrent_file_invalid", "error_code": 30 }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	_, err := client.AddTorrentFile(bytes.NewBufferString("not-a-torrent"), rd.AddTorrentOptions{})
+	apiErr, ok := err.(rd.APIError)
+	assert.True(t, ok)
+	assert.Equal(t, rd.ErrTorrentFileInvalid, apiErr.Code())
+}
+
+func TestClient_InstantAvailability(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents/instantAvailability/05d9df877f471dc4418fe1160cd8ff51b5258f55/baf982e9b3b32c1bf0b40812cd8e75857fb9b0cc", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{
+    "05d9df877f471dc4418fe1160cd8ff51b5258f55": {
+        "rd": [
+            { "1": { "filename": "movie.mkv", "filesize": 957874366 }, "2": { "filename": "movie.srt", "filesize": 30 } },
+            { "1": { "filename": "movie.mkv", "filesize": 957874366 } }
+        ]
+    },
+    "baf982e9b3b32c1bf0b40812cd8e75857fb9b0cc": []
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	availabilities, err := client.InstantAvailability("05d9df877f471dc4418fe1160cd8ff51b5258f55", "baf982e9b3b32c1bf0b40812cd8e75857fb9b0cc")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]rd.HashAvailability{
+		"05d9df877f471dc4418fe1160cd8ff51b5258f55": {
+			"rd": []rd.CachedVariant{
+				{1: {Filename: "movie.mkv", Filesize: 957874366}, 2: {Filename: "movie.srt", Filesize: 30}},
+				{1: {Filename: "movie.mkv", Filesize: 957874366}},
+			},
+		},
+		"baf982e9b3b32c1bf0b40812cd8e75857fb9b0cc": {},
+	}, availabilities)
+	assert.True(t, availabilities["05d9df877f471dc4418fe1160cd8ff51b5258f55"].IsAvailable())
+	assert.False(t, availabilities["baf982e9b3b32c1bf0b40812cd8e75857fb9b0cc"].IsAvailable())
+}
+
+func TestClient_InstantAvailabilityIsBatched(t *testing.T) {
+	var hashes []string
+	for i := 0; i < 100; i++ {
+		hashes = append(hashes, fmt.Sprintf("%040d", i))
+	}
+
+	requested := 0
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.True(t, len(req.URL.String()) <= 2000, "URL is too long")
+		parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/rest/1.0/torrents/instantAvailability/"), "/")
+		requested += len(parts)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"` + parts[0] + `": []}`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	availabilities, err := client.InstantAvailability(hashes...)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, requested)
+	assert.Len(t, availabilities, 3)
+}
+
+func TestClient_AddMagnetLinkWithHost(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents/addMagnet", req.URL.String())
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "magnet-url", req.FormValue("magnet"))
+		assert.Equal(t, "real-debrid.com", req.FormValue("host"))
+
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "id": "MNREAKNMGAG7C", "uri": "" }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	urlInfo, err := client.AddMagnetLink("magnet-url", rd.AddTorrentOptions{Host: "real-debrid.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "MNREAKNMGAG7C", urlInfo.ID)
+}
+
+func TestClient_ActiveCount(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents/activeCount", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "nb": 3, "limit": 25 }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	count, err := client.ActiveCount()
+	assert.NoError(t, err)
+	assert.Equal(t, rd.ActiveCount{Count: 3, Limit: 25}, count)
+}
+
+func TestClient_AvailableHosts(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents/availableHosts", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`[ { "host": "real-debrid.com", "max_file_size": 2000 } ]`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	hosts, err := client.AvailableHosts()
+	assert.NoError(t, err)
+	assert.Equal(t, []rd.TorrentHost{{Host: "real-debrid.com", MaxFileSize: 2000}}, hosts)
+}
+
+func TestClient_GetTorrentsPage(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents?filter=active&limit=1&page=2", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`[ { "id": "DW6CJLD27M7K7", "status": "downloading" } ]`)),
+			Header: map[string][]string{
+				"Content-Type":  {"application/json"},
+				"X-Total-Count": {"12"},
+			},
+		}
+	})
+
+	page, err := client.GetTorrentsPage(rd.ListOptions{Page: 2, Limit: 1, Filter: rd.FilterActive})
+	assert.NoError(t, err)
+	assert.Equal(t, rd.TorrentsPage{
+		PaginatedResponse: rd.PaginatedResponse{Page: 2, CountPerPage: 1, TotalCount: 12},
+		Items:             []rd.TorrentInfo{{ID: "DW6CJLD27M7K7", Status: rd.StatusDownloading}},
+	}, page)
+}
+
+func TestClient_AllTorrents(t *testing.T) {
+	pages := map[string]string{
+		"":  `[ { "id": "1" }, { "id": "2" } ]`,
+		"2": `[ { "id": "3" }, { "id": "4" } ]`,
+		"4": `[ { "id": "5" } ]`,
+	}
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "2", req.URL.Query().Get("limit"))
+		body, ok := pages[req.URL.Query().Get("offset")]
+		assert.True(t, ok, "unexpected offset %s", req.URL.Query().Get("offset"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header: map[string][]string{
+				"Content-Type":  {"application/json"},
+				"X-Total-Count": {"5"},
+			},
+		}
+	})
+
+	var ids []string
+	it := client.AllTorrents(context.Background(), rd.ListOptions{Limit: 2})
+	for it.Next() {
+		ids = append(ids, it.Torrent().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+}
+
+func TestClient_AllTorrentsWithCappedPageSize(t *testing.T) {
+	pages := map[string]string{
+		"":  `[ { "id": "1" }, { "id": "2" } ]`,
+		"2": `[ { "id": "3" }, { "id": "4" } ]`,
+		"4": `[ { "id": "5" } ]`,
+	}
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "1000", req.URL.Query().Get("limit"))
+		body, ok := pages[req.URL.Query().Get("offset")]
+		assert.True(t, ok, "unexpected offset %s", req.URL.Query().Get("offset"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header: map[string][]string{
+				"Content-Type":  {"application/json"},
+				"X-Total-Count": {"5"},
+			},
+		}
+	})
+
+	var ids []string
+	it := client.AllTorrents(context.Background(), rd.ListOptions{Limit: 1000})
+	for it.Next() {
+		ids = append(ids, it.Torrent().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+}
+
+func TestClient_WaitForStatus(t *testing.T) {
+	responses := []string{
+		`{ "id": "XCBYL4ZIYPU42", "status": "queued", "progress": 0 }`,
+		`{ "id": "XCBYL4ZIYPU42", "status": "downloading", "progress": 50, "speed": 1000, "seeders": 5 }`,
+		`{ "id": "XCBYL4ZIYPU42", "status": "downloading", "progress": 50, "speed": 1000, "seeders": 5 }`,
+		`{ "id": "XCBYL4ZIYPU42", "status": "downloaded", "progress": 100 }`,
+	}
+	polls := 0
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/torrents/info/XCBYL4ZIYPU42", req.URL.String())
+		body := responses[polls]
+		polls++
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	var updates []int
+	info, err := client.WaitForStatusWithOptions(context.Background(), "XCBYL4ZIYPU42", rd.WaitOptions{
+		Interval:    time.Millisecond,
+		Backoff:     2,
+		MaxInterval: 3 * time.Millisecond,
+		OnUpdate: func(info rd.TorrentInfo) {
+			updates = append(updates, info.Progress)
+		},
+	}, rd.StatusWaitingFiles, rd.StatusDownloaded)
+	assert.NoError(t, err)
+	assert.Equal(t, rd.StatusDownloaded, info.Status)
+	assert.Equal(t, 4, polls)
+	assert.Equal(t, []int{0, 50, 100}, updates)
+}
+
+func TestClient_WaitForStatusFailsOnErrorState(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "id": "XCBYL4ZIYPU42", "status": "magnet_error" }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	info, err := client.WaitForStatus(context.Background(), "XCBYL4ZIYPU42", rd.StatusDownloaded)
+	assert.Equal(t, rd.TorrentStatusError{ID: "XCBYL4ZIYPU42", Status: rd.StatusMagnetError}, err)
+	assert.Equal(t, rd.StatusMagnetError, info.Status)
+}
+
+func TestClient_WaitForStatusStopsWithContext(t *testing.T) {
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "id": "XCBYL4ZIYPU42", "status": "downloading" }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForStatus(ctx, "XCBYL4ZIYPU42", rd.StatusDownloaded)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestClient_WaitForStatusDoesNotShrinkInterval(t *testing.T) {
+	polls := 0
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		polls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "id": "XCBYL4ZIYPU42", "status": "downloading" }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForStatusWithOptions(ctx, "XCBYL4ZIYPU42", rd.WaitOptions{
+		Interval: 10 * time.Millisecond,
+		Backoff:  0.5,
+	}, rd.StatusDownloaded)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, polls <= 6, "polled %d times", polls)
+}
+
+func TestClient_WaitForStatusDoesNotOverflowInterval(t *testing.T) {
+	polls := 0
+	client := NewTorrentTestClient(func(req *http.Request) *http.Response {
+		polls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{ "id": "XCBYL4ZIYPU42", "status": "downloading" }`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForStatusWithOptions(ctx, "XCBYL4ZIYPU42", rd.WaitOptions{
+		Interval: time.Millisecond,
+		Backoff:  1e300,
+	}, rd.StatusDownloaded)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 2, polls)
+}
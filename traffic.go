@@ -0,0 +1,123 @@
+package rd
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Endpoints
+const (
+	trafficPath        = "/traffic"
+	trafficDetailsPath = "/traffic/details"
+)
+
+const trafficDateFormat = "2006-01-02"
+
+// Possible units of a traffic limit
+const (
+	TrafficLinks     TrafficType = "links"
+	TrafficGigabytes TrafficType = "gigabytes"
+	TrafficBytes     TrafficType = "bytes"
+)
+
+// Possible periods after which a traffic limit resets
+const (
+	ResetDaily   TrafficReset = "daily"
+	ResetWeekly  TrafficReset = "weekly"
+	ResetMonthly TrafficReset = "monthly"
+)
+
+type (
+	TrafficType  string
+	TrafficReset string
+
+	HostTraffic struct {
+		Left  int64        `json:"left"`
+		Bytes int64        `json:"bytes"`
+		Links int          `json:"links"`
+		Limit int64        `json:"limit"`
+		Type  TrafficType  `json:"type"`
+		Extra int64        `json:"extra"`
+		Reset TrafficReset `json:"reset"`
+	}
+
+	// TrafficDay is the traffic used during one day, in total and per host
+	TrafficDay struct {
+		Date  time.Time
+		Bytes int64
+		Hosts map[string]int64
+	}
+
+	TrafficService interface {
+		Get() (map[string]HostTraffic, error)
+		GetContext(ctx context.Context) (map[string]HostTraffic, error)
+		Details(start, end time.Time) ([]TrafficDay, error)
+		DetailsContext(ctx context.Context, start, end time.Time) ([]TrafficDay, error)
+	}
+
+	TrafficClient struct {
+		HTTPDoer
+	}
+)
+
+// Get returns the traffic left on limited hosts, keyed by the main domain of the host
+func (c *TrafficClient) Get() (traffic map[string]HostTraffic, err error) {
+	return c.GetContext(context.Background())
+}
+
+func (c *TrafficClient) GetContext(ctx context.Context) (traffic map[string]HostTraffic, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, trafficPath))
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&traffic)
+	return traffic, err
+}
+
+// Details returns the traffic used per day between start and end, ordered by date. A zero start
+// or end is left to the API default, which is the last seven days. The period can't exceed 31 days.
+func (c *TrafficClient) Details(start, end time.Time) ([]TrafficDay, error) {
+	return c.DetailsContext(context.Background(), start, end)
+}
+
+func (c *TrafficClient) DetailsContext(ctx context.Context, start, end time.Time) ([]TrafficDay, error) {
+	params := map[string]string{}
+	if !start.IsZero() {
+		params["start"] = start.Format(trafficDateFormat)
+	}
+	if !end.IsZero() {
+		params["end"] = end.Format(trafficDateFormat)
+	}
+
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, trafficDetailsPath), params)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	var details map[string]struct {
+		Host  map[string]int64 `json:"host"`
+		Bytes int64            `json:"bytes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return nil, err
+	}
+
+	days := make([]TrafficDay, 0, len(details))
+	for date, detail := range details {
+		d, err := time.Parse(trafficDateFormat, date)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, TrafficDay{Date: d, Bytes: detail.Bytes, Hosts: detail.Host})
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+	return days, nil
+}
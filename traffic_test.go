@@ -0,0 +1,88 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTrafficTestClient(fn TestRoundTripFunc) rd.TrafficService {
+	c := &http.Client{
+		Transport: fn,
+	}
+	return rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c).Traffic
+}
+
+func TestClient_GetTraffic(t *testing.T) {
+	client := NewTrafficTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/traffic", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{
+    "uptobox.com": { "left": 53687091200, "bytes": 1073741824, "links": 2, "limit": 54760833024, "type": "gigabytes", "extra": 0, "reset": "daily" }
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	traffic, err := client.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]rd.HostTraffic{
+		"uptobox.com": {
+			Left:  53687091200,
+			Bytes: 1073741824,
+			Links: 2,
+			Limit: 54760833024,
+			Type:  rd.TrafficGigabytes,
+			Extra: 0,
+			Reset: rd.ResetDaily,
+		},
+	}, traffic)
+}
+
+func TestClient_GetTrafficDetails(t *testing.T) {
+	client := NewTrafficTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/traffic/details?end=2019-01-03&start=2019-01-01", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{
+    "2019-01-03": { "host": { "real-debrid.com": 300 }, "bytes": 300 },
+    "2019-01-01": { "host": { "uptobox.com": 100, "real-debrid.com": 50 }, "bytes": 150 }
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	days, err := client.Details(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []rd.TrafficDay{
+		{
+			Date:  time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			Bytes: 150,
+			Hosts: map[string]int64{"uptobox.com": 100, "real-debrid.com": 50},
+		},
+		{
+			Date:  time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC),
+			Bytes: 300,
+			Hosts: map[string]int64{"real-debrid.com": 300},
+		},
+	}, days)
+}
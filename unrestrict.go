@@ -1,31 +1,73 @@
 package rd
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 )
 
 // Endpoints
 const (
-	unrestrictUrl = apiBaseUrl + "/unrestrict/link"
+	unrestrictPath              = "/unrestrict/link"
+	unrestrictCheckPath         = "/unrestrict/check"
+	unrestrictFolderPath        = "/unrestrict/folder"
+	unrestrictContainerLinkPath = "/unrestrict/containerLink"
+	unrestrictContainerFilePath = "/unrestrict/containerFile"
 )
 
 type (
 	UnrestrictInfo struct {
-		ID         string `json:"id"`
-		Filename   string `json:"filename"`
-		MimeType   string `json:"mimeType"`
-		Filesize   int64  `json:"filesize"`
-		Link       string `json:"link"`
-		Host       string `json:"host"`
-		HostIcon   string `json:"host_icon"`
-		Chunks     int    `json:"chunks"`
-		CRC        int    `json:"crc"`
-		Download   string `json:"download"`
-		Streamable int    `json:"streamable"`
+		ID          string        `json:"id"`
+		Filename    string        `json:"filename"`
+		MimeType    string        `json:"mimeType"`
+		Filesize    int64         `json:"filesize"`
+		Link        string        `json:"link"`
+		Host        string        `json:"host"`
+		HostIcon    string        `json:"host_icon"`
+		Chunks      int           `json:"chunks"`
+		CRC         int           `json:"crc"`
+		Download    string        `json:"download"`
+		Streamable  int           `json:"streamable"`
+		Type        string        `json:"type"`
+		Alternative []Alternative `json:"alternative"`
+	}
+
+	// Alternative is another version of an unrestricted file, usually in a different quality
+	Alternative struct {
+		ID       string `json:"id"`
+		Filename string `json:"filename"`
+		Download string `json:"download"`
+		Type     string `json:"type"`
+	}
+
+	UnrestrictOptions struct {
+		// Password of the protected link
+		Password string
+		// Remote uses the remote traffic of the account, for dedicated servers and account sharing protections
+		Remote bool
+	}
+
+	LinkCheck struct {
+		Host      string `json:"host"`
+		Link      string `json:"link"`
+		Filename  string `json:"filename"`
+		Filesize  int64  `json:"filesize"`
+		Supported int    `json:"supported"`
 	}
 
 	UnrestrictService interface {
 		SimpleUnrestrict(link string) (info UnrestrictInfo, err error)
+		SimpleUnrestrictContext(ctx context.Context, link string) (info UnrestrictInfo, err error)
+		Unrestrict(link string, opts UnrestrictOptions) (info UnrestrictInfo, err error)
+		UnrestrictContext(ctx context.Context, link string, opts UnrestrictOptions) (info UnrestrictInfo, err error)
+		Check(link, password string) (check LinkCheck, err error)
+		CheckContext(ctx context.Context, link, password string) (check LinkCheck, err error)
+		Folder(link string) (links []string, err error)
+		FolderContext(ctx context.Context, link string) (links []string, err error)
+		ContainerLink(url string) (links []string, err error)
+		ContainerLinkContext(ctx context.Context, url string) (links []string, err error)
+		ContainerFile(r io.Reader) (links []string, err error)
+		ContainerFileContext(ctx context.Context, r io.Reader) (links []string, err error)
 	}
 
 	UnrestrictClient struct {
@@ -34,7 +76,28 @@ type (
 )
 
 func (c *UnrestrictClient) SimpleUnrestrict(link string) (info UnrestrictInfo, err error) {
-	resp, err := httpPostForm(c, unrestrictUrl, map[string]string{"link": link})
+	return c.SimpleUnrestrictContext(context.Background(), link)
+}
+
+func (c *UnrestrictClient) SimpleUnrestrictContext(ctx context.Context, link string) (info UnrestrictInfo, err error) {
+	return c.UnrestrictContext(ctx, link, UnrestrictOptions{})
+}
+
+// Unrestrict unrestricts the link using the given options
+func (c *UnrestrictClient) Unrestrict(link string, opts UnrestrictOptions) (info UnrestrictInfo, err error) {
+	return c.UnrestrictContext(context.Background(), link, opts)
+}
+
+func (c *UnrestrictClient) UnrestrictContext(ctx context.Context, link string, opts UnrestrictOptions) (info UnrestrictInfo, err error) {
+	values := map[string]string{"link": link}
+	if opts.Password != "" {
+		values["password"] = opts.Password
+	}
+	if opts.Remote {
+		values["remote"] = "1"
+	}
+
+	resp, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, unrestrictPath), values)
 	if err != nil {
 		return info, err
 	}
@@ -43,3 +106,73 @@ func (c *UnrestrictClient) SimpleUnrestrict(link string) (info UnrestrictInfo, e
 	err = json.NewDecoder(resp.Body).Decode(&info)
 	return info, err
 }
+
+// Check checks if the link is supported and available, without unrestricting it.
+// The password is only sent if it's not empty.
+func (c *UnrestrictClient) Check(link, password string) (check LinkCheck, err error) {
+	return c.CheckContext(context.Background(), link, password)
+}
+
+func (c *UnrestrictClient) CheckContext(ctx context.Context, link, password string) (check LinkCheck, err error) {
+	values := map[string]string{"link": link}
+	if password != "" {
+		values["password"] = password
+	}
+
+	resp, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, unrestrictCheckPath), values)
+	if err != nil {
+		return check, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&check)
+	return check, err
+}
+
+// Folder returns the links contained in a folder link
+func (c *UnrestrictClient) Folder(link string) (links []string, err error) {
+	return c.FolderContext(context.Background(), link)
+}
+
+func (c *UnrestrictClient) FolderContext(ctx context.Context, link string) (links []string, err error) {
+	resp, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, unrestrictFolderPath), map[string]string{"link": link})
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&links)
+	return links, err
+}
+
+// ContainerLink returns the links contained in a remote RSDF, CCF, CCF3 or DLC container file
+func (c *UnrestrictClient) ContainerLink(url string) (links []string, err error) {
+	return c.ContainerLinkContext(context.Background(), url)
+}
+
+func (c *UnrestrictClient) ContainerLinkContext(ctx context.Context, url string) (links []string, err error) {
+	resp, err := httpPostForm(ctx, c, apiURL(c.HTTPDoer, unrestrictContainerLinkPath), map[string]string{"link": url})
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&links)
+	return links, err
+}
+
+// ContainerFile uploads a RSDF, CCF, CCF3 or DLC container file and returns the links it contains
+func (c *UnrestrictClient) ContainerFile(r io.Reader) (links []string, err error) {
+	return c.ContainerFileContext(context.Background(), r)
+}
+
+func (c *UnrestrictClient) ContainerFileContext(ctx context.Context, r io.Reader) (links []string, err error) {
+	resp, err := httpPut(ctx, c, apiURL(c.HTTPDoer, unrestrictContainerFilePath), r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&links)
+	return links, err
+}
@@ -65,3 +65,136 @@ func TestClient_UnrestrictLinkSimple(t *testing.T) {
 		Streamable: 1,
 	}, urlInfo)
 }
+
+func TestClient_CheckLink(t *testing.T) {
+	client := NewUnrestrictTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/unrestrict/check", req.URL.String())
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "test-link-here", req.FormValue("link"))
+		assert.Equal(t, "secret", req.FormValue("password"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(
+				`{ "host": "uptobox.com", "link": "test-link-here", "filename": "helloworld.mkv", "filesize": 1361435465, "supported": 1 }`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	check, err := client.Check("test-link-here", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, rd.LinkCheck{
+		Host:      "uptobox.com",
+		Link:      "test-link-here",
+		Filename:  "helloworld.mkv",
+		Filesize:  1361435465,
+		Supported: 1,
+	}, check)
+}
+
+func TestClient_UnrestrictFolder(t *testing.T) {
+	client := NewUnrestrictTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/unrestrict/folder", req.URL.String())
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "folder-link", req.FormValue("link"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`["first-link", "second-link"]`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	links, err := client.Folder("folder-link")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first-link", "second-link"}, links)
+}
+
+func TestClient_UnrestrictContainerLink(t *testing.T) {
+	client := NewUnrestrictTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/unrestrict/containerLink", req.URL.String())
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "https://example.com/links.dlc", req.FormValue("link"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`["first-link"]`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	links, err := client.ContainerLink("https://example.com/links.dlc")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first-link"}, links)
+}
+
+func TestClient_UnrestrictContainerFile(t *testing.T) {
+	client := NewUnrestrictTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/unrestrict/containerFile", req.URL.String())
+		assert.Equal(t, "PUT", req.Method)
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, "container-content", string(body))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`["first-link", "second-link"]`)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	links, err := client.ContainerFile(bytes.NewBufferString("container-content"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first-link", "second-link"}, links)
+}
+
+func TestClient_UnrestrictWithOptions(t *testing.T) {
+	client := NewUnrestrictTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/unrestrict/link", req.URL.String())
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "test-link-here", req.FormValue("link"))
+		assert.Equal(t, "secret", req.FormValue("password"))
+		assert.Equal(t, "1", req.FormValue("remote"))
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(
+				`{
+    "id": "AIO2UCGAIAQMD",
+    "filename": "helloworld.mkv",
+    "download": "https://30.rdeb.io/d/AIO2UCGAIAQMD/helloworld.mkv",
+    "type": "1080p",
+    "alternative": [
+        { "id": "BIO2UCGAIAQMD", "filename": "helloworld-720p.mkv", "download": "https://30.rdeb.io/d/BIO2UCGAIAQMD/helloworld-720p.mkv", "type": "720p" }
+    ]
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	info, err := client.Unrestrict("test-link-here", rd.UnrestrictOptions{Password: "secret", Remote: true})
+	assert.NoError(t, err)
+	assert.Equal(t, rd.UnrestrictInfo{
+		ID:       "AIO2UCGAIAQMD",
+		Filename: "helloworld.mkv",
+		Download: "https://30.rdeb.io/d/AIO2UCGAIAQMD/helloworld.mkv",
+		Type:     "1080p",
+		Alternative: []rd.Alternative{{
+			ID:       "BIO2UCGAIAQMD",
+			Filename: "helloworld-720p.mkv",
+			Download: "https://30.rdeb.io/d/BIO2UCGAIAQMD/helloworld-720p.mkv",
+			Type:     "720p",
+		}},
+	}, info)
+}
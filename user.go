@@ -0,0 +1,68 @@
+package rd
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Endpoints
+const (
+	userPath = "/user"
+)
+
+// Possible account types
+const (
+	AccountPremium AccountType = "premium"
+	AccountFree    AccountType = "free"
+)
+
+type (
+	AccountType string
+
+	User struct {
+		ID         int         `json:"id"`
+		Username   string      `json:"username"`
+		Email      string      `json:"email"`
+		Points     int         `json:"points"`
+		Locale     string      `json:"locale"`
+		Avatar     string      `json:"avatar"`
+		Type       AccountType `json:"type"`
+		Premium    int64       `json:"premium"`
+		Expiration time.Time   `json:"expiration"`
+	}
+
+	UserService interface {
+		Get() (User, error)
+		GetContext(ctx context.Context) (User, error)
+	}
+
+	UserClient struct {
+		HTTPDoer
+	}
+)
+
+// IsPremium checks if the account currently has a premium subscription
+func (u User) IsPremium() bool {
+	return u.Type == AccountPremium && u.Premium > 0
+}
+
+// PremiumRemaining returns the remaining time of the premium subscription
+func (u User) PremiumRemaining() time.Duration {
+	return time.Duration(u.Premium) * time.Second
+}
+
+func (c *UserClient) Get() (user User, err error) {
+	return c.GetContext(context.Background())
+}
+
+func (c *UserClient) GetContext(ctx context.Context) (user User, err error) {
+	resp, err := httpGet(ctx, c, apiURL(c.HTTPDoer, userPath))
+	if err != nil {
+		return user, err
+	}
+
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&user)
+	return user, err
+}
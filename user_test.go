@@ -0,0 +1,70 @@
+package rd_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nenad/rd"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewUserTestClient(fn TestRoundTripFunc) rd.UserService {
+	c := &http.Client{
+		Transport: fn,
+	}
+	return rd.NewRealDebrid(
+		rd.Token{ExpiresIn: 3600, TokenType: "Bearer", AccessToken: "VALID_TOKEN", RefreshToken: "REFRESH_TOKEN"},
+		c).User
+}
+
+func TestClient_GetUser(t *testing.T) {
+	client := NewUserTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://api.real-debrid.com/rest/1.0/user", req.URL.String())
+		assert.Equal(t, "GET", req.Method)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(bytes.NewBufferString(`{
+    "id": 1234,
+    "username": "helloworld",
+    "email": "hello@world.com",
+    "points": 1000,
+    "locale": "en",
+    "avatar": "https://fcdn.real-debrid.com/images/forum/empty.png",
+    "type": "premium",
+    "premium": 86400,
+    "expiration": "2019-01-20T10:31:01.000Z"
+}`,
+			)),
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+		}
+	})
+
+	user, err := client.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, rd.User{
+		ID:         1234,
+		Username:   "helloworld",
+		Email:      "hello@world.com",
+		Points:     1000,
+		Locale:     "en",
+		Avatar:     "https://fcdn.real-debrid.com/images/forum/empty.png",
+		Type:       rd.AccountPremium,
+		Premium:    86400,
+		Expiration: time.Date(2019, 1, 20, 10, 31, 1, 0, time.UTC),
+	}, user)
+	assert.True(t, user.IsPremium())
+	assert.Equal(t, 24*time.Hour, user.PremiumRemaining())
+}
+
+func TestUser_FreeAccountIsNotPremium(t *testing.T) {
+	user := rd.User{Type: rd.AccountFree}
+	assert.False(t, user.IsPremium())
+	assert.Equal(t, time.Duration(0), user.PremiumRemaining())
+}